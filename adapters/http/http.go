@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"strings"
 
 	"github.com/kpango/glg"
 )
@@ -88,6 +90,50 @@ func NewMultipartReader(data interface{}) (reader io.Reader, boundary string, er
 	return bytes.NewReader(body.Bytes()), writer.Boundary(), nil
 }
 
+// quoteEscaper escapes the file name as multipart.CreateFormFile does
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// NewMultipartFileReader returns a multipart reader with the JSON metadata
+// part followed by a file part
+func NewMultipartFileReader(data interface{}, fileName, contentType string, content []byte) (reader io.Reader, boundary string, err error) {
+	if (data == nil) || (len(content) == 0) {
+		err = ErrorNilData
+		return
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		err = errors.New("error on marshal data: " + err.Error())
+		glg.Warnf("Error on makeReader marshaling json data: %e", err)
+		return
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	metadataHeader := textproto.MIMEHeader{}
+	metadataHeader.Set("Content-Type", "application/json")
+	metadataHeader.Set("Content-ID", "metadata")
+	metadataHeader.Set("Content-Disposition", `form-data; name="metadata"`)
+	fileHeader := textproto.MIMEHeader{}
+	fileHeader.Set("Content-Type", contentType)
+	fileHeader.Set("Content-ID", "file")
+	fileHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(fileName)))
+	for _, part := range []struct {
+		header textproto.MIMEHeader
+		data   []byte
+	}{{metadataHeader, jsonData}, {fileHeader, content}} {
+		w, err := writer.CreatePart(part.header)
+		if err != nil {
+			return nil, "", errors.New("error on create part data: " + err.Error())
+		}
+		if _, err = w.Write(part.data); err != nil {
+			return nil, "", errors.New("error on create part data: " + err.Error())
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return nil, "", errors.New("error on create part data: " + err.Error())
+	}
+	return bytes.NewReader(body.Bytes()), writer.Boundary(), nil
+}
+
 func getWriter(body *bytes.Buffer, data []byte) (*multipart.Writer, error) {
 	writer := multipart.NewWriter(body)
 	metadataHeader := textproto.MIMEHeader{}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"testing"
 
@@ -67,3 +68,35 @@ func TestNewMultipartReader_OK(t *testing.T) {
 	assert.NotNil(t, reader)
 	assert.NotEmpty(t, boudary)
 }
+
+func TestNewMultipartFileReader_OK(t *testing.T) {
+	someObject := struct {
+		Name string `json:"name"`
+	}{Name: "Newman"}
+	reader, boundary, err := NewMultipartFileReader(someObject, "logo.png", "image/png", []byte("png"))
+	assert.Nil(t, err)
+	mr := multipart.NewReader(reader, boundary)
+	part, err := mr.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "metadata", part.FormName())
+	data, _ := ioutil.ReadAll(part)
+	assert.Equal(t, `{"name":"Newman"}`, string(data))
+	part, err = mr.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "logo.png", part.FileName())
+	assert.Equal(t, "image/png", part.Header.Get("Content-Type"))
+	data, _ = ioutil.ReadAll(part)
+	assert.Equal(t, "png", string(data))
+
+	_, _, err = NewMultipartFileReader(someObject, "logo.png", "image/png", nil)
+	assert.Equal(t, ErrorNilData, err)
+
+	reader, boundary, err = NewMultipartFileReader(someObject, `lo"go\.png`, "image/png", []byte("png"))
+	assert.Nil(t, err)
+	mr = multipart.NewReader(reader, boundary)
+	mr.NextPart()
+	part, err = mr.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, `form-data; name="file"; filename="lo\"go\\.png"`, part.Header.Get("Content-Disposition"))
+	assert.Equal(t, `lo"go\.png`, part.FileName())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/arxdsilva/golang-ifood-sdk/mocks"
 	"github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 	"github.com/arxdsilva/golang-ifood-sdk/services/catalog"
+	"github.com/arxdsilva/golang-ifood-sdk/services/disputes"
 	"github.com/arxdsilva/golang-ifood-sdk/services/events"
 	"github.com/arxdsilva/golang-ifood-sdk/services/merchant"
 	"github.com/arxdsilva/golang-ifood-sdk/services/orders"
@@ -25,6 +26,7 @@ type Container struct {
 	CatalogService  catalog.Service
 	EventsService   events.Service
 	OrdersService   orders.Service
+	DisputesService disputes.Service
 }
 
 // New returns a new container
@@ -128,3 +130,19 @@ func (c *Container) GetOrdersService() orders.Service {
 	}
 	return c.OrdersService
 }
+
+// GetDisputesService instantiates a disputes service, also adds it to the container
+func (c *Container) GetDisputesService() disputes.Service {
+	if c.httpadapter == nil {
+		glg.Warn("[GetDisputesService]: http adapter is nil, please set it with Container.GetHttpAdapter")
+		return nil
+	}
+	if c.AuthService == nil {
+		glg.Warn("[GetDisputesService]: please set the authentication service")
+		return nil
+	}
+	if c.DisputesService == nil {
+		c.DisputesService = disputes.New(c.GetHttpAdapter(), c.AuthService)
+	}
+	return c.DisputesService
+}
@@ -6,4 +6,5 @@ require (
 	github.com/gofrs/uuid v4.0.0+incompatible
 	github.com/kpango/glg v1.5.5
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
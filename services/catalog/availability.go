@@ -0,0 +1,251 @@
+package catalog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kpango/glg"
+)
+
+const (
+	// DefaultAvailabilityBatch status updates sent at the same time
+	DefaultAvailabilityBatch = 10
+	// DefaultMinRefreshInterval time between refreshes made for unknown codes
+	DefaultMinRefreshInterval = time.Minute
+)
+
+type (
+	// AvailabilityChange new status of the product or pizza with the external code
+	AvailabilityChange struct {
+		ExternalCode string `json:"externalCode"`
+		Status       string `json:"status"`
+	}
+
+	// AvailabilityResult outcome of an AvailabilityChange
+	AvailabilityResult struct {
+		AvailabilityChange
+		// Resource ResourceProduct or ResourcePizza
+		Resource string
+		ID       string
+		Err      error
+	}
+
+	// AvailabilityIndex ids by external code
+	//
+	// products are indexed by their external code and by the external code
+	// of their items, pizzas by the external code of their PIZZA category
+	AvailabilityIndex struct {
+		Products map[string]string `json:"products"`
+		Pizzas   map[string]string `json:"pizzas"`
+		BuiltAt  time.Time         `json:"builtAt"`
+	}
+
+	// Availability updates product and pizza status by external code
+	Availability struct {
+		service    Service
+		merchantID string
+		catalogID  string
+		// BatchSize updates sent at the same time, a batch
+		// starts when the previous one is done
+		BatchSize int
+		// MinRefreshInterval age the index must have before an unknown
+		// external code refreshes it, so unknown codes do not download
+		// the whole catalog on every Apply
+		MinRefreshInterval time.Duration
+		now                func() time.Time
+		mu                 sync.RWMutex
+		index              AvailabilityIndex
+	}
+)
+
+// NewAvailability for the catalog of a merchant, the index
+// is built on the first Apply or Refresh
+func NewAvailability(service Service, merchantID, catalogID string) *Availability {
+	return &Availability{
+		service:            service,
+		merchantID:         merchantID,
+		catalogID:          catalogID,
+		BatchSize:          DefaultAvailabilityBatch,
+		MinRefreshInterval: DefaultMinRefreshInterval,
+		now:                time.Now,
+	}
+}
+
+// Refresh builds the index again from the products, pizzas and categories
+func (a *Availability) Refresh() (err error) {
+	if err = verifyCategoryItems(a.merchantID, a.catalogID, "category"); err != nil {
+		glg.Error("[SDK] Catalog Availability Refresh: ", err.Error())
+		return
+	}
+	var pz Pizzas
+	var ps Products
+	var cs Categories
+	err = runBounded(2, []func() error{
+		func() (err error) {
+			pz, err = a.service.ListPizzas(a.merchantID)
+			return
+		},
+		func() (err error) {
+			ps, cs, err = catalogContent(a.service, a.merchantID, a.catalogID, DefaultSnapshotWorkers)
+			return
+		},
+	})
+	if err != nil {
+		glg.Error("[SDK] Catalog Availability Refresh: ", err.Error(), " merchant: ", a.merchantID)
+		return
+	}
+	index := AvailabilityIndex{
+		Products: make(map[string]string),
+		Pizzas:   make(map[string]string),
+		BuiltAt:  a.now(),
+	}
+	for _, p := range ps {
+		if p.ExternalCode != "" {
+			index.Products[p.ExternalCode] = p.ID
+		}
+	}
+	pizzas := make(map[string]bool)
+	for _, p := range pz {
+		pizzas[p.ID] = true
+	}
+	for _, c := range cs {
+		if (c.ExternalCode != "") && pizzas[c.Pizza.ID] {
+			index.Pizzas[c.ExternalCode] = c.Pizza.ID
+		}
+		for _, item := range c.Items {
+			if _, ok := index.Products[item.ExternalCode]; !ok && (item.ExternalCode != "") {
+				index.Products[item.ExternalCode] = item.ProductID
+			}
+		}
+	}
+	a.mu.Lock()
+	a.index = index
+	a.mu.Unlock()
+	glg.Infof("[SDK] Catalog Availability Refresh %d products and %d pizzas, merchant '%s'",
+		len(index.Products), len(index.Pizzas), a.merchantID)
+	return
+}
+
+// Index copy of the current index
+func (a *Availability) Index() AvailabilityIndex {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	index := AvailabilityIndex{
+		Products: make(map[string]string, len(a.index.Products)),
+		Pizzas:   make(map[string]string, len(a.index.Pizzas)),
+		BuiltAt:  a.index.BuiltAt,
+	}
+	for code, id := range a.index.Products {
+		index.Products[code] = id
+	}
+	for code, id := range a.index.Pizzas {
+		index.Pizzas[code] = id
+	}
+	return index
+}
+
+// Apply sends the status of every change, the last change of an
+// external code wins. Codes missing from the index refresh it once,
+// when it is older than MinRefreshInterval, err is only set when
+// that refresh fails
+func (a *Availability) Apply(changes ...AvailabilityChange) (results []AvailabilityResult, err error) {
+	latest := make(map[string]int)
+	for i, change := range changes {
+		latest[change.ExternalCode] = i
+	}
+	refreshed := false
+	a.mu.RLock()
+	builtAt := a.index.BuiltAt
+	a.mu.RUnlock()
+	empty := builtAt.IsZero()
+	if empty {
+		if err = a.Refresh(); err != nil {
+			return
+		}
+		refreshed = true
+	} else if a.now().Sub(builtAt) < a.MinRefreshInterval {
+		// too recent to download the catalog again
+		refreshed = true
+	}
+	for i, change := range changes {
+		if latest[change.ExternalCode] != i {
+			continue
+		}
+		res := AvailabilityResult{AvailabilityChange: change}
+		if (change.Status != "AVAILABLE") && (change.Status != "UNAVAILABLE") {
+			res.Err = ErrInvalidStatus
+		} else if res.Resource, res.ID = a.lookup(change.ExternalCode); (res.ID == "") && !refreshed {
+			if err = a.Refresh(); err != nil {
+				return nil, err
+			}
+			refreshed = true
+			res.Resource, res.ID = a.lookup(change.ExternalCode)
+		}
+		if (res.Err == nil) && (res.ID == "") {
+			res.Err = ErrUnknownExternalCode
+		}
+		results = append(results, res)
+	}
+	a.send(results)
+	return
+}
+
+// SetAvailable marks the external codes as AVAILABLE
+func (a *Availability) SetAvailable(externalCodes ...string) ([]AvailabilityResult, error) {
+	return a.Apply(availabilityChanges("AVAILABLE", externalCodes)...)
+}
+
+// SetUnavailable marks the external codes as UNAVAILABLE
+func (a *Availability) SetUnavailable(externalCodes ...string) ([]AvailabilityResult, error) {
+	return a.Apply(availabilityChanges("UNAVAILABLE", externalCodes)...)
+}
+
+func availabilityChanges(status string, externalCodes []string) (changes []AvailabilityChange) {
+	for _, code := range externalCodes {
+		changes = append(changes, AvailabilityChange{ExternalCode: code, Status: status})
+	}
+	return
+}
+
+func (a *Availability) lookup(externalCode string) (resource, id string) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if id, ok := a.index.Products[externalCode]; ok {
+		return ResourceProduct, id
+	}
+	if id, ok := a.index.Pizzas[externalCode]; ok {
+		return ResourcePizza, id
+	}
+	return
+}
+
+// send updates the resolved results a batch at a time
+func (a *Availability) send(results []AvailabilityResult) {
+	size := a.BatchSize
+	if size <= 0 {
+		size = DefaultAvailabilityBatch
+	}
+	var batch []func() error
+	for i := range results {
+		res := &results[i]
+		if res.Err != nil {
+			continue
+		}
+		batch = append(batch, func() error {
+			if res.Resource == ResourcePizza {
+				res.Err = a.service.UpdatePizzaStatus(a.merchantID, res.Status, res.ID)
+			} else {
+				res.Err = a.service.UpdateProductStatus(a.merchantID, res.ID, res.Status)
+			}
+			if res.Err != nil {
+				glg.Errorf("[SDK] Catalog Availability %s '%s': %s", res.Resource, res.ExternalCode, res.Err.Error())
+			}
+			return nil
+		})
+		if len(batch) == size {
+			runBounded(size, batch)
+			batch = nil
+		}
+	}
+	runBounded(size, batch)
+}
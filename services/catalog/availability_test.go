@@ -0,0 +1,134 @@
+package catalog
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stockMenu liveMenu with pizzas, keeping the status updates
+type stockMenu struct {
+	*liveMenu
+	mu       sync.Mutex
+	pizzas   Pizzas
+	statuses map[string]string
+	lists    int
+	running  int
+	peak     int
+	fail     string
+}
+
+func newStockMenu() *stockMenu {
+	m := &stockMenu{liveMenu: newLiveMenu(), pizzas: Pizzas{{ID: "pizza_id"}}, statuses: map[string]string{}}
+	m.categories["cat_pizza"] = CategoryResponse{ID: "cat_pizza", ExternalCode: "c-pizza", Template: "PIZZA",
+		Pizza: Pizza{ID: "pizza_id"}}
+	m.categories["cat_lanches"] = CategoryResponse{ID: "cat_lanches", ExternalCode: "c-lanches",
+		Items: []Item{{ProductID: "id_old"}, {ProductID: "id_legacy", ExternalCode: "i-legacy"}}}
+	return m
+}
+
+func (m *stockMenu) ListPizzas(merchantUUID string) (Pizzas, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lists++
+	return m.pizzas, nil
+}
+
+func (m *stockMenu) update(id, status string) error {
+	m.mu.Lock()
+	m.running++
+	if m.running > m.peak {
+		m.peak = m.running
+	}
+	m.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running--
+	if id == m.fail {
+		return errors.New("update failed")
+	}
+	m.statuses[id] = status
+	return nil
+}
+
+func (m *stockMenu) UpdateProductStatus(merchantUUID, productID, productStatus string) error {
+	return m.update(productID, productStatus)
+}
+
+func (m *stockMenu) UpdatePizzaStatus(merchantUUID, pizzaStatus, pizzaID string) error {
+	return m.update(pizzaID, pizzaStatus)
+}
+
+func TestAvailability_Apply(t *testing.T) {
+	m := newStockMenu()
+	m.fail = "id_manual"
+	a := NewAvailability(m, "merchant_id", "catalog_id")
+	results, err := a.Apply(
+		AvailabilityChange{ExternalCode: "p-burger", Status: "AVAILABLE"},
+		AvailabilityChange{ExternalCode: "p-old", Status: "UNAVAILABLE"},
+		AvailabilityChange{ExternalCode: "i-legacy", Status: "UNAVAILABLE"},
+		AvailabilityChange{ExternalCode: "c-pizza", Status: "UNAVAILABLE"},
+		AvailabilityChange{ExternalCode: "p-burger", Status: "UNAVAILABLE"},
+		AvailabilityChange{ExternalCode: "p-unknown", Status: "UNAVAILABLE"},
+		AvailabilityChange{ExternalCode: "p-old", Status: "SOLD_OUT"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(results))
+	assert.Equal(t, AvailabilityResult{
+		AvailabilityChange: AvailabilityChange{ExternalCode: "i-legacy", Status: "UNAVAILABLE"},
+		Resource:           ResourceProduct,
+		ID:                 "id_legacy",
+	}, results[0])
+	assert.Equal(t, ResourcePizza, results[1].Resource)
+	assert.Equal(t, "id_burger", results[2].ID)
+	assert.Equal(t, ErrUnknownExternalCode, results[3].Err)
+	assert.Equal(t, ErrInvalidStatus, results[4].Err)
+	assert.Equal(t, map[string]string{
+		"id_legacy": "UNAVAILABLE",
+		"pizza_id":  "UNAVAILABLE",
+		"id_burger": "UNAVAILABLE",
+	}, m.statuses)
+	// the first Apply builds the index and p-unknown does not refresh it again
+	assert.Equal(t, 1, m.lists)
+
+	m.products["id_manual"] = Product{ID: "id_manual", ExternalCode: "p-manual"}
+	// an index younger than MinRefreshInterval is not refreshed
+	results, err = a.SetAvailable("p-manual")
+	assert.Nil(t, err)
+	assert.Equal(t, ErrUnknownExternalCode, results[0].Err)
+	assert.Equal(t, 1, m.lists)
+
+	now := time.Now().Add(DefaultMinRefreshInterval)
+	a.now = func() time.Time { return now }
+	results, err = a.SetAvailable("p-manual")
+	assert.Nil(t, err)
+	assert.EqualError(t, results[0].Err, "update failed")
+	assert.Equal(t, 2, m.lists)
+	assert.Equal(t, "id_manual", a.Index().Products["p-manual"])
+	_, err = a.SetUnavailable("p-burger")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, m.lists)
+	_, err = a.SetUnavailable("p-unknown")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, m.lists)
+}
+
+func TestAvailability_Batches(t *testing.T) {
+	m := newStockMenu()
+	a := NewAvailability(m, "merchant_id", "catalog_id")
+	a.BatchSize = 2
+	results, err := a.SetUnavailable("p-burger", "p-old", "i-legacy", "c-pizza", "p-manual")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(results))
+	assert.Equal(t, 4, len(m.statuses))
+	assert.Equal(t, 2, m.peak)
+
+	_, err = NewAvailability(m, "", "catalog_id").SetAvailable("p-burger")
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+	_, err = NewAvailability(&menuService{failOn: "pizzas"}, "merchant_id", "catalog_id").SetAvailable("p-burger")
+	assert.NotNil(t, err)
+}
@@ -55,11 +55,6 @@ func (c *catalogService) ListAllV2(merchantUUID string) (ct Catalogs, err error)
 	return ct, json.Unmarshal(resp, &ct)
 }
 
-// ListChangelogs not implemented
-func (c *catalogService) ListChangelogs(merchantUUID string) (ct Catalogs, err error) {
-	return
-}
-
 // ListUnsellableItems returns all blocked sellable items and why
 func (c *catalogService) ListUnsellableItems(merchantUUID, catalogID string) (ur UnsellableResponse, err error) {
 	if err = verifyCategoryItems(merchantUUID, catalogID, "category"); err != nil {
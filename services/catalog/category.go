@@ -41,6 +41,38 @@ func (c *catalogService) ListAllCategoriesInCatalog(merchantUUID, catalogID stri
 	return cr, json.Unmarshal(resp, &cr)
 }
 
+// ListCategoriesInCatalog gets every category in a catalog as a list
+func (c *catalogService) ListCategoriesInCatalog(merchantUUID, catalogID string) (cr Categories, err error) {
+	if err = verifyCategoryItems(merchantUUID, catalogID, "category"); err != nil {
+		glg.Error("[SDK] Catalog ListCategoriesInCatalog: ", err.Error())
+		return
+	}
+	err = c.auth.Validate()
+	if err != nil {
+		glg.Error("[SDK] Catalog ListCategoriesInCatalog auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	endpoint := v2Endpoint + fmt.Sprintf(
+		"/merchants/%s/catalogs/%s/categories", merchantUUID, catalogID)
+	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Catalog ListCategoriesInCatalog adapter.DoRequest: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Catalog ListCategoriesInCatalog status code: ", status, " merchant: ", merchantUUID)
+		err = fmt.Errorf(
+			"Merchant '%s' could not list categories in catalog '%s'",
+			merchantUUID, catalogID)
+		glg.Error("[SDK] Catalog ListCategoriesInCatalog err: ", err)
+		return
+	}
+	glg.Info("[SDK] List Categories success")
+	return cr, json.Unmarshal(resp, &cr)
+}
+
 // CreateCategoryInCatalog adds a category in a specified catalog
 //
 // resource status 	= [AVAILABLE ||	UNAVAILABLE]
@@ -462,3 +462,48 @@ func TestDeleteCategoryInCatalog_DoReqErr(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "some")
 }
+
+func TestListCategoriesInCatalog_OK(t *testing.T) {
+	categories := `[
+		{"id": "category_2", "sequence": 2, "name": "Bebidas"},
+		{"id": "category_1", "sequence": 1, "name": "Lanches"}
+	]`
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/catalog/v2.0/merchants/merchant_id/catalogs/catalog_id/categories", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, categories)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+	cr, err := catalogService.ListCategoriesInCatalog("merchant_id", "catalog_id")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(cr))
+	assert.Equal(t, "Lanches", cr[1].Name)
+}
+
+func TestListCategoriesInCatalog_StatusBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+	_, err := catalogService.ListCategoriesInCatalog("merchant_id", "catalog_id")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "could not list categories in catalog")
+	_, err = catalogService.ListCategoriesInCatalog("merchant_id", "")
+	assert.Equal(t, ErrCatalogNotSpecified, err)
+}
@@ -0,0 +1,87 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kpango/glg"
+)
+
+// Changelog entity types
+const (
+	EntityProduct     = "PRODUCT"
+	EntityCategory    = "CATEGORY"
+	EntityItem        = "ITEM"
+	EntityPizza       = "PIZZA"
+	EntityOptionGroup = "OPTION_GROUP"
+	EntityOption      = "OPTION"
+)
+
+// Changelog actions
+const (
+	ChangeCreated = "CREATED"
+	ChangeUpdated = "UPDATED"
+	ChangeDeleted = "DELETED"
+)
+
+type (
+	// Changelogs group of ChangelogEntry
+	Changelogs []ChangelogEntry
+
+	// ChangelogEntry single change made to a merchant catalog
+	//
+	// CategoryID is set for items and categories, OptionGroupID for options,
+	// Data holds the entity after the change when the API sends it
+	ChangelogEntry struct {
+		ID            string          `json:"id"`
+		CatalogID     string          `json:"catalogId"`
+		EntityType    string          `json:"entityType"`
+		EntityID      string          `json:"entityId"`
+		Action        string          `json:"action"`
+		CategoryID    string          `json:"categoryId"`
+		OptionGroupID string          `json:"optionGroupId"`
+		CreatedAt     time.Time       `json:"createdAt"`
+		Data          json.RawMessage `json:"data,omitempty"`
+	}
+)
+
+// ListChangelogs lists the catalog changes made in [from, to)
+func (c *catalogService) ListChangelogs(merchantID string, from, to time.Time) (cl Changelogs, err error) {
+	if merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog ListChangelogs: ", err.Error())
+		return
+	}
+	if !from.Before(to) {
+		err = ErrInvalidTimeRange
+		glg.Error("[SDK] Catalog ListChangelogs: ", err.Error())
+		return
+	}
+	err = c.auth.Validate()
+	if err != nil {
+		glg.Error("[SDK] Catalog ListChangelogs auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	query := url.Values{}
+	query.Set("startDate", from.UTC().Format(time.RFC3339))
+	query.Set("endDate", to.UTC().Format(time.RFC3339))
+	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/catalogs/changelog?%s", merchantID, query.Encode())
+	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Catalog ListChangelogs adapter.DoRequest: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Catalog ListChangelogs status code: ", status, " merchant: ", merchantID)
+		err = fmt.Errorf("Merchant '%s' could not list changelogs", merchantID)
+		glg.Error("[SDK] Catalog ListChangelogs err: ", err)
+		return
+	}
+	glg.Infof("[SDK] Catalog ListChangelogs success, merchant '%s'", merchantID)
+	return cl, json.Unmarshal(resp, &cl)
+}
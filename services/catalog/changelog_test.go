@@ -0,0 +1,68 @@
+package catalog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListChangelogs_OK(t *testing.T) {
+	changelogs := `[
+		{"id": "1", "catalogId": "catalog_id", "entityType": "ITEM", "entityId": "item_id",
+			"categoryId": "category_id", "action": "UPDATED", "createdAt": "2021-03-01T10:00:00Z"},
+		{"id": "2", "entityType": "PRODUCT", "entityId": "product_id", "action": "CREATED",
+			"createdAt": "2021-03-01T10:05:00Z", "data": {"id": "product_id", "name": "X-Burger"}}
+	]`
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/catalog/v2.0/merchants/merchant_id/catalogs/changelog", r.URL.Path)
+			assert.Equal(t, "2021-03-01T00:00:00Z", r.URL.Query().Get("startDate"))
+			assert.Equal(t, "2021-03-02T00:00:00Z", r.URL.Query().Get("endDate"))
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, http.MethodGet, r.Method)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, changelogs)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	cl, err := New(adapter, &am).ListChangelogs("merchant_id", from, from.Add(24*time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(cl))
+	assert.Equal(t, EntityItem, cl[0].EntityType)
+	assert.Equal(t, "category_id", cl[0].CategoryID)
+	assert.Equal(t, ChangeCreated, cl[1].Action)
+	assert.Equal(t, from.Add(10*time.Hour+5*time.Minute), cl[1].CreatedAt)
+	assert.JSONEq(t, `{"id": "product_id", "name": "X-Burger"}`, string(cl[1].Data))
+}
+
+func TestListChangelogs_Errors(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+	now := time.Now()
+	_, err := catalogService.ListChangelogs("", now.Add(-time.Hour), now)
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+	_, err = catalogService.ListChangelogs("merchant_id", now, now)
+	assert.Equal(t, ErrInvalidTimeRange, err)
+	_, err = catalogService.ListChangelogs("merchant_id", now.Add(-time.Hour), now)
+	assert.EqualError(t, err, "Merchant 'merchant_id' could not list changelogs")
+}
@@ -0,0 +1,318 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CSV columns read by ImportCSV, headers are matched ignoring case,
+// spaces and dashes ("Original price" is original_price)
+const (
+	ColumnCategory            = "category"
+	ColumnExternalCode        = "external_code"
+	ColumnName                = "name"
+	ColumnDescription         = "description"
+	ColumnPrice               = "price"
+	ColumnOriginalPrice       = "original_price"
+	ColumnServing             = "serving"
+	ColumnDietaryRestrictions = "dietary_restrictions"
+	ColumnEan                 = "ean"
+	ColumnShifts              = "shifts"
+	ColumnStatus              = "status"
+)
+
+var (
+	requiredColumns = []string{ColumnCategory, ColumnName, ColumnPrice, ColumnShifts}
+	weekdays        = []string{"MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"}
+	shiftPattern    = regexp.MustCompile(`^([A-Z,\-]+|\*)\s+(\d{2}:\d{2})-(\d{2}:\d{2})$`)
+	nonCode         = regexp.MustCompile(`[^a-z0-9]+`)
+	accents         = strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+		"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c")
+)
+
+type (
+	// RowError problem found on a CSV row, rows are counted
+	// as in a spreadsheet: the header is row 1
+	RowError struct {
+		Row    int
+		Column string
+		Err    error
+	}
+
+	// ImportErrors every problem found on a CSV import
+	ImportErrors []RowError
+)
+
+func (e RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Err.Error())
+	}
+	return fmt.Sprintf("row %d, column '%s': %s", e.Row, e.Column, e.Err.Error())
+}
+
+// Unwrap the row error cause
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+func (e ImportErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ImportCSV reads a spreadsheet menu, one row per product in a category
+//
+// shifts are written as "MON-FRI 11:00-15:00; SAT,SUN 18:00-23:00", with
+// "*" for every day, and dietary restrictions are comma separated.
+// An empty status is AVAILABLE and an empty serving NOT_APPLICABLE. When
+// there is no external code column products and categories get one from
+// their names, a product listed in many categories is created once.
+//
+// every row is validated and all problems are returned as ImportErrors,
+// each row reporting all of its problems at once;
+// the resulting MenuDocument can be given to a Reconciler
+func ImportCSV(r io.Reader) (md MenuDocument, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return md, fmt.Errorf("could not read the CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, h := range header {
+		columns[columnName(h)] = i
+	}
+	var errs ImportErrors
+	for _, c := range requiredColumns {
+		if _, ok := columns[c]; !ok {
+			errs = append(errs, RowError{Row: 1, Column: c, Err: errors.New("column is missing")})
+		}
+	}
+	if len(errs) > 0 {
+		return md, errs
+	}
+	products := make(map[string]int)
+	categories := make(map[string]int)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, RowError{Row: row, Err: err})
+			continue
+		}
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		rowErrs := len(errs)
+		fail := func(column string, err error) {
+			errs = append(errs, RowError{Row: row, Column: column, Err: err})
+		}
+		product := Product{
+			Name:         value(ColumnName),
+			Description:  value(ColumnDescription),
+			ExternalCode: value(ColumnExternalCode),
+			Serving:      strings.ToUpper(value(ColumnServing)),
+			Ean:          value(ColumnEan),
+		}
+		if product.ExternalCode == "" {
+			product.ExternalCode = externalCode(product.Name)
+		}
+		if product.Serving == "" {
+			product.Serving = "NOT_APPLICABLE"
+		}
+		if restrictions := value(ColumnDietaryRestrictions); restrictions != "" {
+			for _, r := range strings.Split(restrictions, ",") {
+				product.DietaryRestrictions = append(product.DietaryRestrictions, strings.ToUpper(strings.TrimSpace(r)))
+			}
+		}
+		if (product.ExternalCode == "") && (product.Name != "") {
+			fail(ColumnName, fmt.Errorf("%w: no letter or digit in name '%s'", ErrNoExternalCode, product.Name))
+		}
+		shifts, err := parseShifts(value(ColumnShifts))
+		if err != nil {
+			fail(ColumnShifts, err)
+		}
+		product.Shifts = shifts
+		item := MenuItem{ProductExternalCode: product.ExternalCode, Status: strings.ToUpper(value(ColumnStatus)), Shifts: shifts}
+		if item.Status == "" {
+			item.Status = "AVAILABLE"
+		}
+		priceErr := false
+		if item.Price.Value, err = parsePrice(value(ColumnPrice)); err != nil {
+			fail(ColumnPrice, err)
+			priceErr = true
+		}
+		if item.Price.OriginalValue, err = parsePrice(value(ColumnOriginalPrice)); err != nil {
+			fail(ColumnOriginalPrice, err)
+		}
+		categoryName := value(ColumnCategory)
+		if categoryName == "" {
+			fail(ColumnCategory, errors.New("category is empty"))
+		} else if externalCode(categoryName) == "" {
+			fail(ColumnCategory, fmt.Errorf("%w: no letter or digit in category '%s'", ErrNoExternalCode, categoryName))
+		}
+		// fields that could not be parsed are already reported,
+		// placeholders keep the checks below from reporting them again
+		checked, ci := product, item.CategoryItem()
+		if shifts == nil {
+			checked.Shifts, ci.Shifts = []Shift{{}}, []Shift{{}}
+		}
+		if priceErr {
+			ci.Price.Value = 1
+		}
+		if err = checked.verifyFields(); err != nil {
+			fail("", err)
+		}
+		if err = ci.verify(); err != nil {
+			fail("", err)
+		}
+		if len(errs) > rowErrs {
+			continue
+		}
+		if i, ok := products[product.ExternalCode]; !ok {
+			products[product.ExternalCode] = len(md.Products)
+			md.Products = append(md.Products, product)
+		} else if !reflect.DeepEqual(md.Products[i], product) {
+			fail(ColumnName, fmt.Errorf("%w: product '%s' differs from a previous row",
+				ErrDuplicateExternalCode, product.ExternalCode))
+			continue
+		}
+		code := externalCode(categoryName)
+		i, ok := categories[code]
+		if !ok {
+			i = len(md.Categories)
+			categories[code] = i
+			md.Categories = append(md.Categories, MenuCategory{
+				Name: categoryName, ExternalCode: code, Status: "AVAILABLE", Template: "DEFAULT", Sequence: i + 1,
+			})
+		}
+		category := &md.Categories[i]
+		for _, linked := range category.Items {
+			if linked.ProductExternalCode == product.ExternalCode {
+				fail(ColumnName, fmt.Errorf("%w: product '%s' is already in category '%s'",
+					ErrDuplicateExternalCode, product.ExternalCode, categoryName))
+			}
+		}
+		if len(errs) > rowErrs {
+			continue
+		}
+		item.Sequence = len(category.Items) + 1
+		category.Items = append(category.Items, item)
+	}
+	if len(errs) > 0 {
+		return MenuDocument{}, errs
+	}
+	return md, nil
+}
+
+func columnName(header string) string {
+	name := strings.ToLower(strings.TrimSpace(header))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// externalCode from a name, as in "Pão de Queijo" to "pao-de-queijo"
+func externalCode(name string) string {
+	code := accents.Replace(strings.ToLower(name))
+	return strings.Trim(nonCode.ReplaceAllString(code, "-"), "-")
+}
+
+// parsePrice accepts "25.90" and "25,90", empty is 0
+func parsePrice(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price < 0 {
+		return 0, fmt.Errorf("price '%s' is invalid", value)
+	}
+	return price, nil
+}
+
+// parseShifts reads "MON-FRI 11:00-15:00; SAT,SUN 18:00-23:00"
+func parseShifts(value string) (shifts []Shift, err error) {
+	for _, part := range strings.Split(value, ";") {
+		part = strings.ToUpper(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		match := shiftPattern.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("shift '%s' should be like 'MON-FRI 11:00-15:00'", part)
+		}
+		shift := Shift{StartTime: match[2], EndTime: match[3]}
+		if err = verifyClock(shift.StartTime); err != nil {
+			return nil, err
+		}
+		if err = verifyClock(shift.EndTime); err != nil {
+			return nil, err
+		}
+		days, err := parseDays(match[1])
+		if err != nil {
+			return nil, err
+		}
+		shift.Monday, shift.Tuesday, shift.Wednesday, shift.Thursday = days[0], days[1], days[2], days[3]
+		shift.Friday, shift.Saturday, shift.Sunday = days[4], days[5], days[6]
+		shifts = append(shifts, shift)
+	}
+	if len(shifts) == 0 {
+		return nil, ErrNoShifts
+	}
+	return
+}
+
+func parseDays(value string) (days [7]bool, err error) {
+	if value == "*" {
+		return [7]bool{true, true, true, true, true, true, true}, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, last := weekdayIndex(bounds[0]), weekdayIndex(bounds[len(bounds)-1])
+		if first < 0 || last < 0 || last < first {
+			return days, fmt.Errorf("days '%s' should be like 'MON-FRI' or 'SAT,SUN'", part)
+		}
+		for i := first; i <= last; i++ {
+			days[i] = true
+		}
+	}
+	return
+}
+
+func weekdayIndex(day string) int {
+	for i, d := range weekdays {
+		if d == day {
+			return i
+		}
+	}
+	return -1
+}
+
+func verifyClock(clock string) error {
+	var h, m int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &h, &m); err != nil || h > 23 || m > 59 {
+		return fmt.Errorf("time '%s' should be between 00:00 and 23:59", clock)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var menuCSV = `Category,Name,Description,Price,Original Price,Serving,Dietary Restrictions,EAN,Shifts,Status
+Lanches,X-Burger,Pão e carne,"25,90",,SERVES_1,,789100,"MON-FRI 11:00-15:00; SAT,SUN 18:00-23:00",
+Lanches,X-Salada,,22.5,25,serves_1,,,* 00:00-23:59,unavailable
+Promoções,X-Burger,Pão e carne,19.9,"25,90",SERVES_1,,789100,"MON-FRI 11:00-15:00; SAT,SUN 18:00-23:00",AVAILABLE
+Bebidas,Suco Natural,,8,,,"vegan, natural",,* 00:00-23:59,
+`
+
+func TestImportCSV(t *testing.T) {
+	md, err := ImportCSV(strings.NewReader(menuCSV))
+	assert.Nil(t, err)
+	assert.Nil(t, md.Validate())
+	assert.Equal(t, 3, len(md.Products))
+	assert.Equal(t, 3, len(md.Categories))
+
+	burger := md.Products[0]
+	assert.Equal(t, "x-burger", burger.ExternalCode)
+	assert.Equal(t, "Pão e carne", burger.Description)
+	assert.Equal(t, "789100", burger.Ean)
+	assert.Equal(t, []Shift{
+		{StartTime: "11:00", EndTime: "15:00", Monday: true, Tuesday: true, Wednesday: true, Thursday: true, Friday: true},
+		{StartTime: "18:00", EndTime: "23:00", Saturday: true, Sunday: true},
+	}, burger.Shifts)
+	assert.Equal(t, "SERVES_1", md.Products[1].Serving)
+	assert.Equal(t, "NOT_APPLICABLE", md.Products[2].Serving)
+	assert.Equal(t, []string{"VEGAN", "NATURAL"}, md.Products[2].DietaryRestrictions)
+
+	lanches := md.Categories[0]
+	assert.Equal(t, "lanches", lanches.ExternalCode)
+	assert.Equal(t, 1, lanches.Sequence)
+	assert.Equal(t, MenuItem{ProductExternalCode: "x-burger", Status: "AVAILABLE", Price: Price{Value: 25.9},
+		Sequence: 1, Shifts: burger.Shifts}, lanches.Items[0])
+	assert.Equal(t, "UNAVAILABLE", lanches.Items[1].Status)
+	assert.Equal(t, Price{Value: 22.5, OriginalValue: 25}, lanches.Items[1].Price)
+	assert.True(t, lanches.Items[1].Shifts[0].Sunday)
+
+	promos := md.Categories[1]
+	assert.Equal(t, "promocoes", promos.ExternalCode)
+	assert.Equal(t, Price{Value: 19.9, OriginalValue: 25.9}, promos.Items[0].Price)
+	assert.Equal(t, CategoryItem{Status: "AVAILABLE", Price: Price{Value: 19.9, OriginalValue: 25.9},
+		Sequence: 1, Shifts: burger.Shifts}, promos.Items[0].CategoryItem())
+}
+
+func TestImportCSV_ExternalCodeColumn(t *testing.T) {
+	md, err := ImportCSV(strings.NewReader("category,external-code,name,price,shifts\nLanches,SKU-1,X-Burger,10,* 10:00-22:00\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "SKU-1", md.Products[0].ExternalCode)
+	assert.Equal(t, "SKU-1", md.Categories[0].Items[0].ProductExternalCode)
+}
+
+func TestImportCSV_Errors(t *testing.T) {
+	_, err := ImportCSV(strings.NewReader("category,name\n"))
+	assert.EqualError(t, err, "row 1, column 'price': column is missing\nrow 1, column 'shifts': column is missing")
+
+	csv := `category,name,price,original price,serving,dietary restrictions,shifts,status
+Lanches,X-Burger,abc,,,,* 10:00-22:00,
+,X-Salada,10,,,,MON-FUN 10:00-22:00,
+Lanches,X-Egg,10,,SERVES_9,,* 10:00-22:00,
+Lanches,X-Bacon,0,,,,* 10:00-22:00,
+Lanches,X-Tudo,10,,,GLUTEN,* 10:00-22:00,CLOSED
+Lanches,X-Frango,10,,,,* 10:00-22:00,
+Lanches,X-Frango,12,,,,* 10:00-22:00,
+Bebidas,X-Frango,10,,SERVES_2,,* 10:00-22:00,
+Bebidas,Suco,10,,,,* 25:00-22:00,
+`
+	_, err = ImportCSV(strings.NewReader(csv))
+	errs := ImportErrors{}
+	assert.True(t, errors.As(err, &errs))
+	rows := []int{}
+	for _, e := range errs {
+		rows = append(rows, e.Row)
+	}
+	assert.Equal(t, []int{2, 3, 3, 4, 5, 6, 6, 8, 9, 10}, rows)
+	assert.Equal(t, "row 2, column 'price': price 'abc' is invalid", errs[0].Error())
+	assert.Equal(t, ColumnShifts, errs[1].Column)
+	assert.Equal(t, "row 3, column 'category': category is empty", errs[2].Error())
+	assert.True(t, errors.Is(errs[4], ErrNoPrice))
+	assert.True(t, errors.Is(errs[6], ErrInvalidStatus))
+	assert.True(t, errors.Is(errs[7], ErrDuplicateExternalCode))
+	assert.Contains(t, errs[8].Error(), "differs from a previous row")
+	assert.Contains(t, errs[9].Error(), "time '25:00' should be between 00:00 and 23:59")
+}
+
+func TestImportCSV_EveryRowError(t *testing.T) {
+	csv := `category,name,price,shifts,status
+Lanches,,abc,* 10:00-22:00,CLOSED
+Lanches,🍔🍟,10,* 10:00-22:00,
+🍹,Suco,10,* 10:00-22:00,
+`
+	_, err := ImportCSV(strings.NewReader(csv))
+	errs := ImportErrors{}
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, 5, len(errs))
+	// a bad price, a missing name and a bad status are reported together
+	assert.Equal(t, "row 2, column 'price': price 'abc' is invalid", errs[0].Error())
+	assert.Equal(t, ErrNoProductName, errs[1].Err)
+	assert.Equal(t, ErrInvalidStatus, errs[2].Err)
+	// names without letters or digits give no external code
+	assert.Equal(t, 3, errs[3].Row)
+	assert.True(t, errors.Is(errs[3], ErrNoExternalCode))
+	assert.Equal(t, RowError{Row: 4, Column: ColumnCategory, Err: errs[4].Err}, errs[4])
+	assert.True(t, errors.Is(errs[4], ErrNoExternalCode))
+}
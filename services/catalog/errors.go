@@ -59,4 +59,67 @@ var (
 	ErrInvalidStatus = errors.New("INVALID status, it should be 'AVAILABLE' or 'UNAVAILABLE'")
 	// ErrNoShifts no shift
 	ErrNoShifts = errors.New("Item needs at least one shift")
+
+	// ErrNoExternalCode menu document entry without external code
+	ErrNoExternalCode = errors.New("menu entry needs an external code")
+	// ErrDuplicateExternalCode two menu document entries share an external code
+	ErrDuplicateExternalCode = errors.New("external code is used more than once")
+	// ErrUnknownProduct item references a product missing from the menu document
+	ErrUnknownProduct = errors.New("item references an unknown product")
+	// ErrUnresolvedReference operation depends on a resource that has no id
+	ErrUnresolvedReference = errors.New("referenced resource has no id")
+
+	// ErrNoOptionGroupName no option group name
+	ErrNoOptionGroupName = errors.New("Option group needs a name")
+	// ErrNoOptionGroupID no option group id
+	ErrNoOptionGroupID = errors.New("optionGroupID not specified")
+	// ErrInvalidOptionLimits option group min and max do not fit
+	ErrInvalidOptionLimits = errors.New("Option group min should be between 0 and max, and max at least 1")
+	// ErrNoOptionName no option name
+	ErrNoOptionName = errors.New("Option needs a name")
+	// ErrNoOptionID no option id
+	ErrNoOptionID = errors.New("optionID not specified")
+	// ErrInvalidOptionPrice negative option price
+	ErrInvalidOptionPrice = errors.New("Option price can not be negative")
+
+	// ErrInvalidTimeRange start of a time range is not before its end
+	ErrInvalidTimeRange = errors.New("time range start should be before its end")
+
+	// ErrImageTooLarge image file is bigger than MaxImageSize
+	ErrImageTooLarge = errors.New("image is larger than the maximum size")
+	// ErrInvalidImageFormat image is not a JPEG or PNG
+	ErrInvalidImageFormat = errors.New("image should be a JPEG or PNG")
+	// ErrInvalidImageDimensions image width or height out of bounds
+	ErrInvalidImageDimensions = errors.New("image dimensions are out of bounds")
+
+	// ErrNoPriceTarget price update without external code or product id
+	ErrNoPriceTarget = errors.New("price update needs an external code or a product id")
+	// ErrInvalidPromotionPrice promotion original value below its value
+	ErrInvalidPromotionPrice = errors.New("original value should be at least the price value")
+	// ErrItemNotFound product is not linked to any category of the catalog
+	ErrItemNotFound = errors.New("product has no item in the catalog")
+
+	// ErrUnknownExternalCode external code of no product or pizza of the catalog
+	ErrUnknownExternalCode = errors.New("external code matches no product or pizza")
+
+	// ErrNameTooLong name longer than the API accepts
+	ErrNameTooLong = errors.New("name is too long")
+	// ErrDescriptionTooLong description longer than the API accepts
+	ErrDescriptionTooLong = errors.New("description is too long")
+	// ErrInvalidServing serving not listed in the API docs
+	ErrInvalidServing = errors.New("serving should be SERVES_1 to SERVES_4 or NOT_APPLICABLE")
+	// ErrInvalidDietaryRestriction restriction not listed in the API docs
+	ErrInvalidDietaryRestriction = errors.New("dietary restriction does not exist in the API docs")
+	// ErrNoCategoryName no category name
+	ErrNoCategoryName = errors.New("Category needs a name")
+	// ErrInvalidTemplate category template other than DEFAULT or PIZZA
+	ErrInvalidTemplate = errors.New("template should be 'DEFAULT' or 'PIZZA'")
+	// ErrEmptyCategory category without items
+	ErrEmptyCategory = errors.New("category has no items")
+	// ErrInvalidShiftTime shift time not written as HH:MM
+	ErrInvalidShiftTime = errors.New("shift time should be HH:MM between 00:00 and 23:59")
+	// ErrNoShiftDays shift without any week day
+	ErrNoShiftDays = errors.New("shift needs at least one week day")
+	// ErrOverlappingShifts shifts sharing a week day and time
+	ErrOverlappingShifts = errors.New("shift overlaps")
 )
@@ -0,0 +1,216 @@
+package catalog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	// decoders for image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/kpango/glg"
+)
+
+// Local limits checked before an image is uploaded
+const (
+	MaxImageSize      = 10 << 20
+	MinImageWidth     = 300
+	MinImageHeight    = 275
+	MaxImageDimension = 4000
+)
+
+type (
+	// ImageResult outcome of an image in a bulk upload
+	ImageResult struct {
+		File string
+		Hash string
+		// Path to set as the product or item image
+		Path string
+		// Skipped the same content was already uploaded
+		Skipped bool
+		Err     error
+	}
+
+	// ImageUploader uploads images once per content, keeping
+	// the path of every content hash already uploaded
+	ImageUploader struct {
+		service    Service
+		merchantID string
+		mu         sync.Mutex
+		uploaded   map[string]string
+	}
+
+	imageMetadata struct {
+		FileName    string `json:"fileName"`
+		ContentType string `json:"contentType"`
+	}
+)
+
+// verifyImage checks size, format and dimensions, returning the content type
+func verifyImage(data []byte) (contentType string, err error) {
+	if len(data) > MaxImageSize {
+		return "", fmt.Errorf("%w: %d bytes, max %d", ErrImageTooLarge, len(data), MaxImageSize)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrInvalidImageFormat
+	}
+	if (config.Width < MinImageWidth) || (config.Height < MinImageHeight) ||
+		(config.Width > MaxImageDimension) || (config.Height > MaxImageDimension) {
+		return "", fmt.Errorf("%w: %dx%d, should be from %dx%d to %dx%d", ErrInvalidImageDimensions,
+			config.Width, config.Height, MinImageWidth, MinImageHeight, MaxImageDimension, MaxImageDimension)
+	}
+	return "image/" + format, nil
+}
+
+// UploadImage sends a JPEG or PNG image and returns its path,
+// to be used as Product.Image
+func (c *catalogService) UploadImage(merchantID, fileName string, img io.Reader) (path string, err error) {
+	if merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog UploadImage: ", err.Error())
+		return
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(img, MaxImageSize+1))
+	if err != nil {
+		glg.Error("[SDK] Catalog UploadImage ReadAll: ", err.Error())
+		return
+	}
+	contentType, err := verifyImage(data)
+	if err != nil {
+		glg.Error("[SDK] Catalog UploadImage verifyImage: ", err.Error(), " file: ", fileName)
+		return
+	}
+	if err = c.auth.Validate(); err != nil {
+		glg.Error("[SDK] Catalog UploadImage auth.Validate: ", err.Error())
+		return
+	}
+	metadata := imageMetadata{FileName: fileName, ContentType: contentType}
+	body, boundary, err := httpadapter.NewMultipartFileReader(metadata, fileName, contentType, data)
+	if err != nil {
+		glg.Error("[SDK] Catalog UploadImage NewMultipartFileReader: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	headers["Content-Type"] = "multipart/form-data; boundary=" + boundary
+	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/image/upload", merchantID)
+	resp, status, err := c.adapter.DoRequest(http.MethodPost, endpoint, body, headers)
+	if err != nil {
+		glg.Error("[SDK] Catalog UploadImage adapter.DoRequest: ", err.Error())
+		return
+	}
+	if (status != http.StatusOK) && (status != http.StatusCreated) {
+		glg.Error("[SDK] Catalog UploadImage status code: ", status, " merchant: ", merchantID)
+		err = fmt.Errorf("Merchant '%s' could not upload image '%s'", merchantID, fileName)
+		glg.Error("[SDK] Catalog UploadImage err: ", err)
+		return
+	}
+	uploaded := struct {
+		Path string `json:"path"`
+	}{}
+	if err = json.Unmarshal(resp, &uploaded); err != nil {
+		return
+	}
+	glg.Infof("[SDK] Catalog UploadImage '%s' success, merchant '%s'", fileName, merchantID)
+	return uploaded.Path, nil
+}
+
+// UploadImageFile uploads the image at filePath
+func (c *catalogService) UploadImageFile(merchantID, filePath string) (path string, err error) {
+	f, err := openImage(filePath)
+	if err != nil {
+		glg.Error("[SDK] Catalog UploadImageFile openImage: ", err.Error())
+		return
+	}
+	defer f.Close()
+	return c.UploadImage(merchantID, filepath.Base(filePath), f)
+}
+
+// openImage opens the image file, refusing files bigger than
+// MaxImageSize before reading them
+func openImage(filePath string) (f *os.File, err error) {
+	if f, err = os.Open(filePath); err != nil {
+		return
+	}
+	info, err := f.Stat()
+	if err == nil && info.Size() > MaxImageSize {
+		err = fmt.Errorf("%w: %d bytes, max %d", ErrImageTooLarge, info.Size(), MaxImageSize)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return
+}
+
+// NewImageUploader starts from the paths of hashes already uploaded,
+// as returned by Uploaded on a previous run, and may be nil
+func NewImageUploader(service Service, merchantID string, uploaded map[string]string) *ImageUploader {
+	u := &ImageUploader{service: service, merchantID: merchantID, uploaded: make(map[string]string)}
+	for hash, path := range uploaded {
+		u.uploaded[hash] = path
+	}
+	return u
+}
+
+// Upload sends every file whose content was not uploaded yet
+func (u *ImageUploader) Upload(files ...string) (results []ImageResult) {
+	for _, file := range files {
+		results = append(results, u.upload(file))
+	}
+	return
+}
+
+// Uploaded paths by content hash
+func (u *ImageUploader) Uploaded() map[string]string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	uploaded := make(map[string]string, len(u.uploaded))
+	for hash, path := range u.uploaded {
+		uploaded[hash] = path
+	}
+	return uploaded
+}
+
+func (u *ImageUploader) upload(file string) (res ImageResult) {
+	res.File = file
+	f, err := openImage(file)
+	if err != nil {
+		res.Err = err
+		return
+	}
+	data, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		res.Err = err
+		return
+	}
+	sum := sha256.Sum256(data)
+	res.Hash = hex.EncodeToString(sum[:])
+	u.mu.Lock()
+	path, ok := u.uploaded[res.Hash]
+	u.mu.Unlock()
+	if ok {
+		res.Path, res.Skipped = path, true
+		return
+	}
+	if res.Path, res.Err = u.service.UploadImage(u.merchantID, filepath.Base(file), bytes.NewReader(data)); res.Err != nil {
+		return
+	}
+	u.mu.Lock()
+	u.uploaded[res.Hash] = res.Path
+	u.mu.Unlock()
+	return
+}
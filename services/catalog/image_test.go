@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+func pngImage(width, height int) []byte {
+	buf := &bytes.Buffer{}
+	png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height)))
+	return buf.Bytes()
+}
+
+func imageServer(t *testing.T, uploads *int) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*uploads++
+			assert.Equal(t, "/catalog/v2.0/merchants/merchant_id/image/upload", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, http.MethodPost, r.Method)
+			file, header, err := r.FormFile("file")
+			assert.Nil(t, err)
+			assert.Equal(t, "image/png", header.Header.Get("Content-Type"))
+			data, _ := ioutil.ReadAll(file)
+			_, err = png.DecodeConfig(bytes.NewReader(data))
+			assert.Nil(t, err)
+			assert.JSONEq(t, fmt.Sprintf(`{"fileName": "%s", "contentType": "image/png"}`, header.Filename),
+				r.FormValue("metadata"))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"path": "merchant_id/%d-%s"}`, *uploads, header.Filename)
+		}),
+	)
+}
+
+func TestUploadImage_OK(t *testing.T) {
+	uploads := 0
+	ts := imageServer(t, &uploads)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+	path, err := catalogService.UploadImage("merchant_id", "burger.png", bytes.NewReader(pngImage(400, 300)))
+	assert.Nil(t, err)
+	assert.Equal(t, "merchant_id/1-burger.png", path)
+
+	dir, _ := ioutil.TempDir("", "images")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "soda.png")
+	ioutil.WriteFile(file, pngImage(300, 275), 0600)
+	path, err = catalogService.UploadImageFile("merchant_id", file)
+	assert.Nil(t, err)
+	assert.Equal(t, "merchant_id/2-soda.png", path)
+}
+
+func TestUploadImage_Validation(t *testing.T) {
+	uploads := 0
+	ts := imageServer(t, &uploads)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+
+	_, err := catalogService.UploadImage("", "burger.png", bytes.NewReader(pngImage(400, 300)))
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+	_, err = catalogService.UploadImage("merchant_id", "burger.gif", bytes.NewReader([]byte("GIF89a")))
+	assert.Equal(t, ErrInvalidImageFormat, err)
+	_, err = catalogService.UploadImage("merchant_id", "burger.png", bytes.NewReader(pngImage(200, 300)))
+	assert.True(t, errors.Is(err, ErrInvalidImageDimensions))
+	assert.EqualError(t, err, "image dimensions are out of bounds: 200x300, should be from 300x275 to 4000x4000")
+	buf := &bytes.Buffer{}
+	jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 5000, 300)), nil)
+	_, err = catalogService.UploadImage("merchant_id", "burger.jpg", buf)
+	assert.True(t, errors.Is(err, ErrInvalidImageDimensions))
+	_, err = catalogService.UploadImage("merchant_id", "huge.png", bytes.NewReader(make([]byte, MaxImageSize+10)))
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+	_, err = catalogService.UploadImageFile("merchant_id", "missing.png")
+	assert.NotNil(t, err)
+	dir, _ := ioutil.TempDir("", "images")
+	defer os.RemoveAll(dir)
+	huge := filepath.Join(dir, "huge.png")
+	ioutil.WriteFile(huge, make([]byte, MaxImageSize+1), 0600)
+	_, err = catalogService.UploadImageFile("merchant_id", huge)
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+	assert.True(t, errors.Is(NewImageUploader(catalogService, "merchant_id", nil).Upload(huge)[0].Err, ErrImageTooLarge))
+	assert.Equal(t, 0, uploads)
+}
+
+func TestUploadImage_StatusError(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	_, err := New(adapter, &am).UploadImage("merchant_id", "burger.png", bytes.NewReader(pngImage(400, 300)))
+	assert.EqualError(t, err, "Merchant 'merchant_id' could not upload image 'burger.png'")
+}
+
+func TestImageUploader(t *testing.T) {
+	uploads := 0
+	ts := imageServer(t, &uploads)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+
+	dir, _ := ioutil.TempDir("", "images")
+	defer os.RemoveAll(dir)
+	burger, copied, soda := filepath.Join(dir, "burger.png"), filepath.Join(dir, "copy.png"), filepath.Join(dir, "soda.png")
+	ioutil.WriteFile(burger, pngImage(400, 300), 0600)
+	ioutil.WriteFile(copied, pngImage(400, 300), 0600)
+	ioutil.WriteFile(soda, pngImage(300, 300), 0600)
+
+	uploader := NewImageUploader(catalogService, "merchant_id", nil)
+	results := uploader.Upload(burger, copied, filepath.Join(dir, "missing.png"))
+	assert.Equal(t, 1, uploads)
+	assert.False(t, results[0].Skipped)
+	assert.Equal(t, "merchant_id/1-burger.png", results[0].Path)
+	assert.True(t, results[1].Skipped)
+	assert.Equal(t, results[0].Hash, results[1].Hash)
+	assert.Equal(t, "merchant_id/1-burger.png", results[1].Path)
+	assert.NotNil(t, results[2].Err)
+
+	// a later run only uploads what changed
+	uploader = NewImageUploader(catalogService, "merchant_id", uploader.Uploaded())
+	results = uploader.Upload(burger, soda)
+	assert.Equal(t, 2, uploads)
+	assert.True(t, results[0].Skipped)
+	assert.Equal(t, "merchant_id/2-soda.png", results[1].Path)
+	assert.Equal(t, 2, len(uploader.Uploaded()))
+}
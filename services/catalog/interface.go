@@ -1,10 +1,17 @@
 package catalog
 
+import (
+	"io"
+	"time"
+)
+
 // Service describes the catalog abstraction
 type Service interface {
 	ListAllV2(merchantID string) (Catalogs, error)
+	ListChangelogs(merchantID string, from, to time.Time) (Changelogs, error)
 	ListUnsellableItems(merchantUUID, catalogID string) (UnsellableResponse, error)
 	ListAllCategoriesInCatalog(merchantUUID, catalogID string) (CategoryResponse, error)
+	ListCategoriesInCatalog(merchantUUID, catalogID string) (Categories, error)
 	CreateCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, template, externalCode string) (CategoryCreateResponse, error)
 	GetCategoryInCatalog(merchantUUID, catalogID, categoryID string) (CategoryResponse, error)
 	EditCategoryInCatalog(merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode string, sequence int) (CategoryCreateResponse, error)
@@ -22,4 +29,16 @@ type Service interface {
 	UnlinkPizzaCategory(merchantUUID, pizzaID, categoryID string) error
 	LinkPizzaToCategory(merchantUUID, categoryID string, pizza Pizza) error
 	UnlinkProductToCategory(merchantUUID, categoryID, productID string) error
+	CreateItem(merchantID, categoryID, productID string, ci CategoryItem) (ProductLink, error)
+	EditItem(merchantID, categoryID, productID string, ci CategoryItem) (ProductLink, error)
+	DeleteItem(merchantID, categoryID, productID string) error
+	ListOptionGroups(merchantID string) (OptionGroups, error)
+	CreateOptionGroup(merchantID string, og OptionGroup) (OptionGroup, error)
+	EditOptionGroup(merchantID string, og OptionGroup) (OptionGroup, error)
+	DeleteOptionGroup(merchantID, optionGroupID string) error
+	CreateOption(merchantID, optionGroupID string, option Option) (Option, error)
+	EditOption(merchantID, optionGroupID string, option Option) (Option, error)
+	DeleteOption(merchantID, optionGroupID, optionID string) error
+	UploadImage(merchantID, fileName string, image io.Reader) (string, error)
+	UploadImageFile(merchantID, filePath string) (string, error)
 }
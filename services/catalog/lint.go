@@ -0,0 +1,207 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits the API enforces on names and descriptions
+const (
+	MaxProductNameLength  = 65
+	MaxCategoryNameLength = 100
+	MaxDescriptionLength  = 2000
+)
+
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+type (
+	// LintIssue problem found at a path of a MenuDocument,
+	// as "categories[2].items[5].shifts[0].startTime"
+	LintIssue struct {
+		Path string
+		Err  error
+	}
+
+	// LintIssues every problem found by Lint
+	LintIssues []LintIssue
+
+	linter struct {
+		issues LintIssues
+	}
+)
+
+func (i LintIssue) Error() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Err.Error())
+}
+
+// Unwrap the issue cause
+func (i LintIssue) Unwrap() error {
+	return i.Err
+}
+
+func (e LintIssues) Error() string {
+	lines := make([]string, len(e))
+	for i, issue := range e {
+		lines[i] = issue.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Err returns the issues as an error, nil when there are none
+//
+// a nil LintIssues stored in an error interface is not a nil error,
+// so use Err instead of returning the issues as an error
+func (e LintIssues) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Lint checks the whole document offline and reports every issue found,
+// in document order. Besides the checks made by the requests it looks for
+// duplicate external codes, HH:MM shift times, shifts without days or
+// overlapping each other and categories without items
+//
+// name and description limits count characters, not bytes
+func Lint(md MenuDocument) (issues LintIssues) {
+	l := &linter{}
+	products := make(map[string]bool)
+	for i, p := range md.Products {
+		path := fmt.Sprintf("products[%d]", i)
+		l.externalCode(path, p.ExternalCode, products)
+		l.product(path, p)
+	}
+	categories := make(map[string]bool)
+	for i, c := range md.Categories {
+		path := fmt.Sprintf("categories[%d]", i)
+		l.externalCode(path, c.ExternalCode, categories)
+		l.category(path, c, products)
+	}
+	return l.issues
+}
+
+func (l *linter) add(path string, err error) {
+	l.issues = append(l.issues, LintIssue{Path: path, Err: err})
+}
+
+func (l *linter) externalCode(path, code string, seen map[string]bool) {
+	switch {
+	case code == "":
+		l.add(path+".externalCode", ErrNoExternalCode)
+	case seen[code]:
+		l.add(path+".externalCode", fmt.Errorf("%w: '%s'", ErrDuplicateExternalCode, code))
+	}
+	seen[code] = true
+}
+
+func (l *linter) product(path string, p Product) {
+	if p.Name == "" {
+		l.add(path+".name", ErrNoProductName)
+	} else if n := utf8.RuneCountInString(p.Name); n > MaxProductNameLength {
+		l.add(path+".name", fmt.Errorf("%w: %d characters, max %d", ErrNameTooLong, n, MaxProductNameLength))
+	}
+	if n := utf8.RuneCountInString(p.Description); n > MaxDescriptionLength {
+		l.add(path+".description", fmt.Errorf("%w: %d characters, max %d",
+			ErrDescriptionTooLong, n, MaxDescriptionLength))
+	}
+	if _, ok := servings[p.Serving]; !ok {
+		l.add(path+".serving", fmt.Errorf("%w: '%s'", ErrInvalidServing, p.Serving))
+	}
+	for i, restriction := range p.DietaryRestrictions {
+		if _, ok := dietaryRestrictions[restriction]; !ok {
+			l.add(fmt.Sprintf("%s.dietaryRestrictions[%d]", path, i),
+				fmt.Errorf("%w: '%s'", ErrInvalidDietaryRestriction, restriction))
+		}
+	}
+	l.shifts(path, p.Shifts)
+}
+
+func (l *linter) category(path string, c MenuCategory, products map[string]bool) {
+	if c.Name == "" {
+		l.add(path+".name", ErrNoCategoryName)
+	} else if n := utf8.RuneCountInString(c.Name); n > MaxCategoryNameLength {
+		l.add(path+".name", fmt.Errorf("%w: %d characters, max %d", ErrNameTooLong, n, MaxCategoryNameLength))
+	}
+	l.status(path, c.Status)
+	if template := c.template(); (template != "DEFAULT") && (template != "PIZZA") {
+		l.add(path+".template", fmt.Errorf("%w: '%s'", ErrInvalidTemplate, template))
+	}
+	if len(c.Items) == 0 {
+		l.add(path+".items", ErrEmptyCategory)
+	}
+	linked := make(map[string]bool)
+	for i, item := range c.Items {
+		itemPath := fmt.Sprintf("%s.items[%d]", path, i)
+		code := item.ProductExternalCode
+		switch {
+		case !products[code]:
+			l.add(itemPath+".productExternalCode", fmt.Errorf("%w: '%s'", ErrUnknownProduct, code))
+		case linked[code]:
+			l.add(itemPath+".productExternalCode", fmt.Errorf("%w: '%s'", ErrDuplicateExternalCode, code))
+		}
+		linked[code] = true
+		l.status(itemPath, item.Status)
+		if item.Price.Value <= 0 {
+			l.add(itemPath+".price.value", ErrNoItemPrice)
+		}
+		if (item.Price.OriginalValue != 0) && (item.Price.OriginalValue < item.Price.Value) {
+			l.add(itemPath+".price.originalValue", fmt.Errorf("%w: original value %.2f, value %.2f",
+				ErrInvalidPromotionPrice, item.Price.OriginalValue, item.Price.Value))
+		}
+		l.shifts(itemPath, item.Shifts)
+	}
+}
+
+func (l *linter) status(path, status string) {
+	if (status != "AVAILABLE") && (status != "UNAVAILABLE") {
+		l.add(path+".status", ErrInvalidStatus)
+	}
+}
+
+// shifts checks the times and days of each shift, and that shifts
+// sharing a week day do not overlap
+func (l *linter) shifts(path string, shifts []Shift) {
+	if len(shifts) == 0 {
+		l.add(path+".shifts", ErrNoShifts)
+		return
+	}
+	for i, s := range shifts {
+		shiftPath := fmt.Sprintf("%s.shifts[%d]", path, i)
+		valid := true
+		for _, clock := range []struct{ field, value string }{{"startTime", s.StartTime}, {"endTime", s.EndTime}} {
+			if !clockPattern.MatchString(clock.value) {
+				l.add(shiftPath+"."+clock.field, fmt.Errorf("%w: '%s'", ErrInvalidShiftTime, clock.value))
+				valid = false
+			}
+		}
+		days := s.days()
+		if days == [7]bool{} {
+			l.add(shiftPath, ErrNoShiftDays)
+			continue
+		}
+		if !valid {
+			continue
+		}
+		for j, other := range shifts[:i] {
+			if !clockPattern.MatchString(other.StartTime) || !clockPattern.MatchString(other.EndTime) {
+				continue
+			}
+			otherDays := other.days()
+			shared := false
+			for d := range days {
+				shared = shared || (days[d] && otherDays[d])
+			}
+			// HH:MM strings compare as times
+			if shared && (s.StartTime < other.EndTime) && (other.StartTime < s.EndTime) {
+				l.add(shiftPath, fmt.Errorf("%w with shifts[%d]", ErrOverlappingShifts, j))
+			}
+		}
+	}
+}
+
+func (s Shift) days() [7]bool {
+	return [7]bool{s.Monday, s.Tuesday, s.Wednesday, s.Thursday, s.Friday, s.Saturday, s.Sunday}
+}
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lintDocument() MenuDocument {
+	lunch := Shift{StartTime: "11:00", EndTime: "15:00", Monday: true, Tuesday: true}
+	dinner := Shift{StartTime: "18:00", EndTime: "23:00", Monday: true}
+	return MenuDocument{
+		Products: []Product{
+			{Name: "Burger", ExternalCode: "p-burger", Serving: "SERVES_1", Shifts: []Shift{lunch, dinner}},
+			{Name: "Soda", ExternalCode: "p-soda", Serving: "NOT_APPLICABLE", Shifts: []Shift{lunch},
+				DietaryRestrictions: []string{"VEGAN"}},
+		},
+		Categories: []MenuCategory{{
+			Name: "Lanches", ExternalCode: "c-lanches", Status: "AVAILABLE",
+			Items: []MenuItem{
+				{ProductExternalCode: "p-burger", Status: "AVAILABLE", Price: Price{Value: 20, OriginalValue: 25}, Shifts: []Shift{dinner}},
+				{ProductExternalCode: "p-soda", Status: "UNAVAILABLE", Price: Price{Value: 5}, Shifts: []Shift{lunch}},
+			},
+		}},
+	}
+}
+
+func TestLint_OK(t *testing.T) {
+	assert.Nil(t, Lint(lintDocument()))
+	assert.Nil(t, Lint(lintDocument()).Err())
+
+	// limits count characters, "ã" takes two bytes
+	md := lintDocument()
+	md.Products[0].Name = strings.Repeat("ã", MaxProductNameLength)
+	md.Products[0].Description = strings.Repeat("ç", MaxDescriptionLength)
+	md.Categories[0].Name = strings.Repeat("é", MaxCategoryNameLength)
+	assert.Nil(t, Lint(md).Err())
+}
+
+func TestLint_Issues(t *testing.T) {
+	md := lintDocument()
+	md.Products[0].Name = strings.Repeat("x", 66)
+	md.Products[0].DietaryRestrictions = []string{"VEGAN", "KETO"}
+	md.Products[0].Shifts = append(md.Products[0].Shifts,
+		Shift{StartTime: "14:00", EndTime: "16:00", Tuesday: true},
+		Shift{StartTime: "9:00", EndTime: "24:00", Sunday: true},
+		Shift{StartTime: "10:00", EndTime: "11:00"})
+	md.Products[1].ExternalCode = "p-burger"
+	md.Products[1].Serving = "SERVES_10"
+	md.Products = append(md.Products, Product{Name: "Fries", Serving: "SERVES_1"})
+	md.Categories[0].Items[0].Price = Price{Value: 20, OriginalValue: 15}
+	md.Categories[0].Items = append(md.Categories[0].Items,
+		MenuItem{ProductExternalCode: "p-burger", Status: "SOLD_OUT", Shifts: []Shift{{StartTime: "10:00", EndTime: "11:00", Friday: true}}},
+		MenuItem{ProductExternalCode: "p-pizza", Status: "AVAILABLE", Price: Price{Value: 30}})
+	md.Categories = append(md.Categories, MenuCategory{ExternalCode: "c-lanches", Status: "AVAILABLE", Template: "COMBO"})
+
+	issues := Lint(md)
+	paths := make([]string, len(issues))
+	for i, issue := range issues {
+		paths[i] = issue.Path
+	}
+	assert.Equal(t, []string{
+		"products[0].name",
+		"products[0].dietaryRestrictions[1]",
+		"products[0].shifts[2]",
+		"products[0].shifts[3].startTime",
+		"products[0].shifts[3].endTime",
+		"products[0].shifts[4]",
+		"products[1].externalCode",
+		"products[1].serving",
+		"products[2].externalCode",
+		"products[2].shifts",
+		"categories[0].items[0].price.originalValue",
+		"categories[0].items[1].productExternalCode",
+		"categories[0].items[2].productExternalCode",
+		"categories[0].items[2].status",
+		"categories[0].items[2].price.value",
+		"categories[0].items[3].productExternalCode",
+		"categories[0].items[3].shifts",
+		"categories[1].externalCode",
+		"categories[1].name",
+		"categories[1].template",
+		"categories[1].items",
+	}, paths)
+	assert.True(t, errors.Is(issues[0], ErrNameTooLong))
+	assert.EqualError(t, issues[2], "products[0].shifts[2]: shift overlaps with shifts[0]")
+	assert.True(t, errors.Is(issues[3], ErrInvalidShiftTime))
+	assert.Equal(t, ErrNoShiftDays, issues[5].Err)
+	assert.True(t, errors.Is(issues[6], ErrDuplicateExternalCode))
+	assert.True(t, errors.Is(issues[12], ErrDuplicateExternalCode))
+	assert.True(t, errors.Is(issues[15], ErrUnknownProduct))
+	assert.Equal(t, ErrEmptyCategory, issues[20].Err)
+	assert.Equal(t, 21, len(strings.Split(issues.Error(), "\n")))
+	assert.Equal(t, issues.Error(), issues.Err().Error())
+}
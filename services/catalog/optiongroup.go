@@ -0,0 +1,262 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/kpango/glg"
+)
+
+func (og *OptionGroup) verify() (err error) {
+	if og.Name == "" {
+		return ErrNoOptionGroupName
+	}
+	if (og.Status != "AVAILABLE") && (og.Status != "UNAVAILABLE") {
+		return ErrInvalidStatus
+	}
+	if (og.Max < 1) || (og.Min < 0) || (og.Min > og.Max) {
+		return fmt.Errorf("%w: min %d, max %d", ErrInvalidOptionLimits, og.Min, og.Max)
+	}
+	if (len(og.Options) > 0) && (og.Max > len(og.Options)) {
+		return fmt.Errorf("%w: max %d, %d options", ErrInvalidOptionLimits, og.Max, len(og.Options))
+	}
+	for _, option := range og.Options {
+		if err = option.verify(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (o *Option) verify() (err error) {
+	if o.Name == "" {
+		return ErrNoOptionName
+	}
+	if (o.Status != "AVAILABLE") && (o.Status != "UNAVAILABLE") {
+		return ErrInvalidStatus
+	}
+	if (o.Price.Value < 0) || (o.Price.OriginalValue < 0) {
+		return ErrInvalidOptionPrice
+	}
+	return
+}
+
+// ListOptionGroups of a merchant with their options
+func (c *catalogService) ListOptionGroups(merchantID string) (ogs OptionGroups, err error) {
+	if merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog ListOptionGroups: ", err.Error())
+		return
+	}
+	if err = c.auth.Validate(); err != nil {
+		glg.Error("[SDK] Catalog ListOptionGroups auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/optionGroups", merchantID)
+	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Catalog ListOptionGroups adapter.DoRequest: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Catalog ListOptionGroups status code: ", status, " merchant: ", merchantID)
+		err = fmt.Errorf("Merchant '%s' could not list option groups", merchantID)
+		glg.Error("[SDK] Catalog ListOptionGroups err: ", err)
+		return
+	}
+	glg.Infof("[SDK] Catalog ListOptionGroups success, merchant '%s'", merchantID)
+	return ogs, json.Unmarshal(resp, &ogs)
+}
+
+// CreateOptionGroup in a merchant
+//
+// min and max are how many options the customer picks,
+// 0 <= min <= max, max >= 1 and no more than the options given
+func (c *catalogService) CreateOptionGroup(merchantID string, og OptionGroup) (cog OptionGroup, err error) {
+	if merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog CreateOptionGroup: ", err.Error())
+		return
+	}
+	if err = og.verify(); err != nil {
+		glg.Error("[SDK] Catalog CreateOptionGroup verify: ", err.Error())
+		return
+	}
+	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/optionGroups", merchantID)
+	resp, err := c.sendOptions("CreateOptionGroup", merchantID, "create option group", http.MethodPost, endpoint, og, http.StatusCreated)
+	if err != nil {
+		return
+	}
+	glg.Infof("[SDK] Catalog CreateOptionGroup success, merchant '%s'", merchantID)
+	return cog, json.Unmarshal(resp, &cog)
+}
+
+// EditOptionGroup of a merchant
+func (c *catalogService) EditOptionGroup(merchantID string, og OptionGroup) (cog OptionGroup, err error) {
+	if merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog EditOptionGroup: ", err.Error())
+		return
+	}
+	if og.ID == "" {
+		err = ErrNoOptionGroupID
+		glg.Error("[SDK] Catalog EditOptionGroup: ", err.Error())
+		return
+	}
+	if err = og.verify(); err != nil {
+		glg.Error("[SDK] Catalog EditOptionGroup verify: ", err.Error())
+		return
+	}
+	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/optionGroups/%s", merchantID, og.ID)
+	resp, err := c.sendOptions("EditOptionGroup", merchantID, fmt.Sprintf("edit option group id '%s'", og.ID),
+		http.MethodPatch, endpoint, og, http.StatusOK)
+	if err != nil {
+		return
+	}
+	glg.Infof("[SDK] Catalog EditOptionGroup id '%s' success, merchant '%s'", og.ID, merchantID)
+	return cog, json.Unmarshal(resp, &cog)
+}
+
+// DeleteOptionGroup of a merchant
+func (c *catalogService) DeleteOptionGroup(merchantID, optionGroupID string) (err error) {
+	if merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog DeleteOptionGroup: ", err.Error())
+		return
+	}
+	if optionGroupID == "" {
+		err = ErrNoOptionGroupID
+		glg.Error("[SDK] Catalog DeleteOptionGroup: ", err.Error())
+		return
+	}
+	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/optionGroups/%s", merchantID, optionGroupID)
+	_, err = c.sendOptions("DeleteOptionGroup", merchantID, fmt.Sprintf("delete option group id '%s'", optionGroupID),
+		http.MethodDelete, endpoint, nil, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return
+	}
+	glg.Infof("[SDK] Catalog DeleteOptionGroup id '%s' success, merchant '%s'", optionGroupID, merchantID)
+	return
+}
+
+// CreateOption in an option group
+func (c *catalogService) CreateOption(merchantID, optionGroupID string, option Option) (co Option, err error) {
+	if err = verifyOptionGroup(merchantID, optionGroupID); err != nil {
+		glg.Error("[SDK] Catalog CreateOption: ", err.Error())
+		return
+	}
+	if err = option.verify(); err != nil {
+		glg.Error("[SDK] Catalog CreateOption verify: ", err.Error())
+		return
+	}
+	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/optionGroups/%s/options", merchantID, optionGroupID)
+	resp, err := c.sendOptions("CreateOption", merchantID, fmt.Sprintf("create option in option group id '%s'", optionGroupID),
+		http.MethodPost, endpoint, option, http.StatusCreated)
+	if err != nil {
+		return
+	}
+	glg.Infof("[SDK] Catalog CreateOption success, option group '%s', merchant '%s'", optionGroupID, merchantID)
+	return co, json.Unmarshal(resp, &co)
+}
+
+// EditOption of an option group
+func (c *catalogService) EditOption(merchantID, optionGroupID string, option Option) (co Option, err error) {
+	if err = verifyOptionGroup(merchantID, optionGroupID); err != nil {
+		glg.Error("[SDK] Catalog EditOption: ", err.Error())
+		return
+	}
+	if option.ID == "" {
+		err = ErrNoOptionID
+		glg.Error("[SDK] Catalog EditOption: ", err.Error())
+		return
+	}
+	if err = option.verify(); err != nil {
+		glg.Error("[SDK] Catalog EditOption verify: ", err.Error())
+		return
+	}
+	endpoint := v2Endpoint + fmt.Sprintf(
+		"/merchants/%s/optionGroups/%s/options/%s", merchantID, optionGroupID, option.ID)
+	resp, err := c.sendOptions("EditOption", merchantID, fmt.Sprintf("edit option id '%s'", option.ID),
+		http.MethodPatch, endpoint, option, http.StatusOK)
+	if err != nil {
+		return
+	}
+	glg.Infof("[SDK] Catalog EditOption id '%s' success, merchant '%s'", option.ID, merchantID)
+	return co, json.Unmarshal(resp, &co)
+}
+
+// DeleteOption from an option group
+func (c *catalogService) DeleteOption(merchantID, optionGroupID, optionID string) (err error) {
+	if err = verifyOptionGroup(merchantID, optionGroupID); err != nil {
+		glg.Error("[SDK] Catalog DeleteOption: ", err.Error())
+		return
+	}
+	if optionID == "" {
+		err = ErrNoOptionID
+		glg.Error("[SDK] Catalog DeleteOption: ", err.Error())
+		return
+	}
+	endpoint := v2Endpoint + fmt.Sprintf(
+		"/merchants/%s/optionGroups/%s/options/%s", merchantID, optionGroupID, optionID)
+	_, err = c.sendOptions("DeleteOption", merchantID, fmt.Sprintf("delete option id '%s'", optionID),
+		http.MethodDelete, endpoint, nil, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return
+	}
+	glg.Infof("[SDK] Catalog DeleteOption id '%s' success, merchant '%s'", optionID, merchantID)
+	return
+}
+
+// sendOptions authenticated option group request expecting one of the
+// given status, a nil body sends no payload, action describes the
+// request on the error
+func (c *catalogService) sendOptions(name, merchantID, action, method, endpoint string, body interface{}, expected ...int) (resp []byte, err error) {
+	if err = c.auth.Validate(); err != nil {
+		glg.Error("[SDK] Catalog ", name, " auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	resp, status, err := c.doWithBody(method, endpoint, body, headers)
+	if err != nil {
+		glg.Error("[SDK] Catalog ", name, " adapter.DoRequest: ", err.Error())
+		return
+	}
+	for _, e := range expected {
+		if status == e {
+			return
+		}
+	}
+	badResp := &apiError{}
+	json.Unmarshal(resp, badResp)
+	glg.Error("[SDK] Catalog ", name, " status code: ", status, " merchant: ", merchantID, " detail: ", badResp.Details.Code)
+	err = fmt.Errorf("Merchant '%s' could not %s, code: '%s'", merchantID, action, badResp.Details.Code)
+	glg.Error("[SDK] Catalog ", name, " err: ", err)
+	return
+}
+
+func (c *catalogService) doWithBody(method, endpoint string, body interface{}, headers map[string]string) ([]byte, int, error) {
+	if body == nil {
+		return c.adapter.DoRequest(method, endpoint, nil, headers)
+	}
+	headers["Content-Type"] = "application/json"
+	reader, err := httpadapter.NewJsonReader(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return c.adapter.DoRequest(method, endpoint, reader, headers)
+}
+
+func verifyOptionGroup(merchantID, optionGroupID string) error {
+	if merchantID == "" {
+		return ErrMerchantNotSpecified
+	}
+	if optionGroupID == "" {
+		return ErrNoOptionGroupID
+	}
+	return nil
+}
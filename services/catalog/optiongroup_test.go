@@ -0,0 +1,202 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/mocks"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var optionGroup = OptionGroup{
+	Name:   "Adicionais",
+	Status: "AVAILABLE",
+	Min:    0,
+	Max:    2,
+	Options: []Option{
+		{Name: "Bacon", Status: "AVAILABLE", Price: Price{Value: 4}},
+		{Name: "Cheddar", Status: "AVAILABLE", Price: Price{Value: 3}},
+	},
+}
+
+func TestListOptionGroups_OK(t *testing.T) {
+	groups := `[{"id": "group_id", "name": "Adicionais", "min": 0, "max": 3,
+		"options": [{"id": "option_1", "name": "Bacon", "price": {"value": 4}}, {"id": "option_2", "name": "Cheddar"}]}]`
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/catalog/v2.0/merchants/merchant_id/optionGroups", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, http.MethodGet, r.Method)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, groups)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ogs, err := New(adapter, &am).ListOptionGroups("merchant_id")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ogs[0].Options))
+	assert.Equal(t, float64(4), ogs[0].Options[0].Price.Value)
+	assert.Equal(t, 3, ogs[0].Max)
+}
+
+func TestCreateOptionGroup_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/catalog/v2.0/merchants/merchant_id/optionGroups", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header["Content-Type"][0])
+			body, _ := ioutil.ReadAll(r.Body)
+			og := OptionGroup{}
+			assert.Nil(t, json.Unmarshal(body, &og))
+			assert.Equal(t, optionGroup, og)
+			og.ID = "group_id"
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(og)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	og, err := New(adapter, &am).CreateOptionGroup("merchant_id", optionGroup)
+	assert.Nil(t, err)
+	assert.Equal(t, "group_id", og.ID)
+}
+
+func TestOptionGroup_Validation(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	catalogService := New(adapter, &am)
+
+	_, err := catalogService.CreateOptionGroup("", optionGroup)
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+	og := optionGroup
+	og.Name = ""
+	_, err = catalogService.CreateOptionGroup("merchant_id", og)
+	assert.Equal(t, ErrNoOptionGroupName, err)
+	og = optionGroup
+	og.Min, og.Max = 2, 1
+	_, err = catalogService.CreateOptionGroup("merchant_id", og)
+	assert.True(t, errors.Is(err, ErrInvalidOptionLimits))
+	og.Min, og.Max = 0, 0
+	_, err = catalogService.CreateOptionGroup("merchant_id", og)
+	assert.True(t, errors.Is(err, ErrInvalidOptionLimits))
+	og.Min, og.Max = -1, 2
+	_, err = catalogService.CreateOptionGroup("merchant_id", og)
+	assert.True(t, errors.Is(err, ErrInvalidOptionLimits))
+	og.Min, og.Max = 0, 3
+	_, err = catalogService.CreateOptionGroup("merchant_id", og)
+	assert.True(t, errors.Is(err, ErrInvalidOptionLimits))
+	og = optionGroup
+	og.Max = 1
+	og.Options = []Option{{Name: "Bacon", Status: "AVAILABLE", Price: Price{Value: -1}}}
+	_, err = catalogService.CreateOptionGroup("merchant_id", og)
+	assert.Equal(t, ErrInvalidOptionPrice, err)
+	_, err = catalogService.EditOptionGroup("merchant_id", optionGroup)
+	assert.Equal(t, ErrNoOptionGroupID, err)
+	err = catalogService.DeleteOptionGroup("merchant_id", "")
+	assert.Equal(t, ErrNoOptionGroupID, err)
+
+	_, err = catalogService.CreateOption("merchant_id", "", Option{})
+	assert.Equal(t, ErrNoOptionGroupID, err)
+	_, err = catalogService.CreateOption("merchant_id", "group_id", Option{Status: "AVAILABLE"})
+	assert.Equal(t, ErrNoOptionName, err)
+	_, err = catalogService.CreateOption("merchant_id", "group_id", Option{Name: "Bacon"})
+	assert.Equal(t, ErrInvalidStatus, err)
+	_, err = catalogService.EditOption("merchant_id", "group_id", Option{Name: "Bacon", Status: "AVAILABLE"})
+	assert.Equal(t, ErrNoOptionID, err)
+	err = catalogService.DeleteOption("merchant_id", "group_id", "")
+	assert.Equal(t, ErrNoOptionID, err)
+}
+
+func TestOptionEndpoints_OK(t *testing.T) {
+	calls := []string{}
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			switch r.Method {
+			case http.MethodPost:
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"id": "option_id", "name": "Bacon"}`)
+			case http.MethodPatch:
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, `{"id": "option_id", "name": "Bacon", "min": 1, "max": 2}`)
+			default:
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+	option := Option{Name: "Bacon", Status: "AVAILABLE", Price: Price{Value: 4}}
+
+	created, err := catalogService.CreateOption("merchant_id", "group_id", option)
+	assert.Nil(t, err)
+	assert.Equal(t, "option_id", created.ID)
+	option.ID = created.ID
+	_, err = catalogService.EditOption("merchant_id", "group_id", option)
+	assert.Nil(t, err)
+	assert.Nil(t, catalogService.DeleteOption("merchant_id", "group_id", "option_id"))
+	og := optionGroup
+	og.ID, og.Min, og.Max = "group_id", 1, 2
+	edited, err := catalogService.EditOptionGroup("merchant_id", og)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, edited.Max)
+	assert.Nil(t, catalogService.DeleteOptionGroup("merchant_id", "group_id"))
+	assert.Equal(t, []string{
+		"POST /catalog/v2.0/merchants/merchant_id/optionGroups/group_id/options",
+		"PATCH /catalog/v2.0/merchants/merchant_id/optionGroups/group_id/options/option_id",
+		"DELETE /catalog/v2.0/merchants/merchant_id/optionGroups/group_id/options/option_id",
+		"PATCH /catalog/v2.0/merchants/merchant_id/optionGroups/group_id",
+		"DELETE /catalog/v2.0/merchants/merchant_id/optionGroups/group_id",
+	}, calls)
+}
+
+func TestOptionEndpoints_Errors(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprintf(w, `{"details": {"code": "DuplicatedExternalCode"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	catalogService := New(adapter, &am)
+	_, err := catalogService.CreateOptionGroup("merchant_id", optionGroup)
+	assert.EqualError(t, err, "Merchant 'merchant_id' could not create option group, code: 'DuplicatedExternalCode'")
+	_, err = catalogService.ListOptionGroups("merchant_id")
+	assert.NotNil(t, err)
+	err = catalogService.DeleteOption("merchant_id", "group_id", "option_id")
+	assert.EqualError(t, err, "Merchant 'merchant_id' could not delete option id 'option_id', code: 'DuplicatedExternalCode'")
+
+	validateErr := auth.AuthMock{}
+	validateErr.On("Validate").Return(errors.New("some err"))
+	_, err = New(adapter, &validateErr).CreateOption("merchant_id", "group_id", optionGroup.Options[0])
+	assert.EqualError(t, err, "some err")
+
+	httpmock := &mocks.HttpClientMock{}
+	httpmock.On("Do", mock.Anything).Once().Return(nil, errors.New("some err"))
+	adapter = httpadapter.New(httpmock, "ts.URL")
+	_, err = New(adapter, &am).ListOptionGroups("merchant_id")
+	assert.EqualError(t, err, "some err")
+}
@@ -57,30 +57,15 @@ type (
 	}
 )
 
-func (p *Product) verifyFields() (err error) {
-	if p.Name == "" {
-		return ErrNoProductName
-	}
-	if len(p.Name) > 65 {
-		return errors.New("Name len is higher than 65 characters")
-	}
-	if len(p.Description) > 2000 {
-		return errors.New("Description len is higher than 2000 characters")
-	}
-	if len(p.Shifts) == 0 {
-		return errors.New("Product needs at least 1 shift")
-	}
-	serving := map[string]string{
+var (
+	servings = map[string]string{
 		"SERVES_1":       "",
 		"SERVES_2":       "",
 		"SERVES_3":       "",
 		"SERVES_4":       "",
 		"NOT_APPLICABLE": "",
 	}
-	if _, ok := serving[p.Serving]; !ok {
-		return errors.New("Serving not valid, verify docs: https://developer.ifood.com.br/reference#productcontroller_createproduct")
-	}
-	restrictions := map[string]string{
+	dietaryRestrictions = map[string]string{
 		"VEGETARIAN":      "",
 		"VEGAN":           "",
 		"ORGANIC":         "",
@@ -90,9 +75,27 @@ func (p *Product) verifyFields() (err error) {
 		"ALCOHOLIC_DRINK": "",
 		"NATURAL":         "",
 	}
+)
+
+func (p *Product) verifyFields() (err error) {
+	if p.Name == "" {
+		return ErrNoProductName
+	}
+	if len(p.Name) > 65 {
+		return errors.New("Name len is higher than 65 characters")
+	}
+	if len(p.Description) > 2000 {
+		return errors.New("Description len is higher than 2000 characters")
+	}
+	if len(p.Shifts) == 0 {
+		return errors.New("Product needs at least 1 shift")
+	}
+	if _, ok := servings[p.Serving]; !ok {
+		return errors.New("Serving not valid, verify docs: https://developer.ifood.com.br/reference#productcontroller_createproduct")
+	}
 	if len(p.DietaryRestrictions) > 0 {
 		for _, restriction := range p.DietaryRestrictions {
-			if _, ok := restrictions[restriction]; !ok {
+			if _, ok := dietaryRestrictions[restriction]; !ok {
 				return fmt.Errorf(
 					"restriction '%s' does not exist in docs, see: https://developer.ifood.com.br/reference#productcontroller_createproduct",
 					restriction)
@@ -0,0 +1,570 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kpango/glg"
+	"gopkg.in/yaml.v3"
+)
+
+// Resources and actions of a reconcile plan
+const (
+	ResourceProduct  = "PRODUCT"
+	ResourceCategory = "CATEGORY"
+	ResourceItem     = "ITEM"
+	ResourcePizza    = "PIZZA"
+
+	ActionCreate = "CREATE"
+	ActionUpdate = "UPDATE"
+	ActionDelete = "DELETE"
+	ActionLink   = "LINK"
+	ActionUnlink = "UNLINK"
+)
+
+type (
+	// MenuDocument desired state of a catalog
+	//
+	// products and categories are matched against the live catalog by
+	// external code, live entries without one are never touched
+	MenuDocument struct {
+		Products   []Product      `json:"products"`
+		Categories []MenuCategory `json:"categories"`
+	}
+
+	// MenuCategory desired category and the products linked to it
+	MenuCategory struct {
+		Name         string     `json:"name"`
+		ExternalCode string     `json:"externalCode"`
+		Status       string     `json:"status"`
+		Template     string     `json:"template"`
+		Sequence     int        `json:"sequence"`
+		Items        []MenuItem `json:"items"`
+	}
+
+	// MenuItem desired link of a product to its category
+	MenuItem struct {
+		ProductExternalCode string  `json:"productExternalCode"`
+		Status              string  `json:"status"`
+		Price               Price   `json:"price"`
+		Sequence            int     `json:"sequence"`
+		Shifts              []Shift `json:"shifts"`
+	}
+
+	// Operation single change of a Plan
+	//
+	// items are identified by the product external code
+	// and the external code of their category
+	Operation struct {
+		Action       string   `json:"action"`
+		Resource     string   `json:"resource"`
+		ExternalCode string   `json:"externalCode"`
+		Category     string   `json:"category,omitempty"`
+		Changes      []string `json:"changes,omitempty"`
+
+		id         string
+		categoryID string
+		product    Product
+		category   MenuCategory
+		item       MenuItem
+	}
+
+	// Plan operations that make the live catalog match a MenuDocument,
+	// in the order they are applied
+	Plan struct {
+		MerchantID string      `json:"merchantId"`
+		CatalogID  string      `json:"catalogId"`
+		Operations []Operation `json:"operations"`
+
+		productIDs  map[string]string
+		categoryIDs map[string]string
+	}
+
+	// OperationResult outcome of an applied Operation
+	OperationResult struct {
+		Operation
+		Err error `json:"-"`
+	}
+
+	// Reconciler plans and applies a MenuDocument on a merchant catalog
+	Reconciler struct {
+		service    Service
+		merchantID string
+		catalogID  string
+		// Workers concurrent requests used to read the live catalog
+		Workers int
+		// DeleteMissing plans the delete of categories and products missing
+		// from the document, only products linked in a category of this
+		// catalog are deleted, a product also linked in other catalogs
+		// leaves them too
+		DeleteMissing bool
+	}
+
+	liveCategory struct {
+		CategoryResponse
+		items map[string]Item
+	}
+)
+
+// ReadMenuDocument decodes a JSON or YAML menu document
+func ReadMenuDocument(data []byte) (md MenuDocument, err error) {
+	var doc interface{}
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return
+	}
+	// YAML keys follow the JSON names of the menu types
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	return md, json.Unmarshal(data, &md)
+}
+
+// Validate checks the document before any request is made
+func (md MenuDocument) Validate() (err error) {
+	products := make(map[string]bool)
+	for _, p := range md.Products {
+		if p.ExternalCode == "" {
+			return fmt.Errorf("%w: product '%s'", ErrNoExternalCode, p.Name)
+		}
+		if products[p.ExternalCode] {
+			return fmt.Errorf("%w: product '%s'", ErrDuplicateExternalCode, p.ExternalCode)
+		}
+		products[p.ExternalCode] = true
+		if err = p.verifyFields(); err != nil {
+			return fmt.Errorf("product '%s': %w", p.ExternalCode, err)
+		}
+	}
+	categories := make(map[string]bool)
+	for _, c := range md.Categories {
+		if c.ExternalCode == "" {
+			return fmt.Errorf("%w: category '%s'", ErrNoExternalCode, c.Name)
+		}
+		if categories[c.ExternalCode] {
+			return fmt.Errorf("%w: category '%s'", ErrDuplicateExternalCode, c.ExternalCode)
+		}
+		categories[c.ExternalCode] = true
+		err = verifyNewCategoryInCatalog("merchant", "catalog", c.Name, c.Status, c.template())
+		if err != nil {
+			return fmt.Errorf("category '%s': %w", c.ExternalCode, err)
+		}
+		linked := make(map[string]bool)
+		for _, i := range c.Items {
+			if !products[i.ProductExternalCode] {
+				return fmt.Errorf("%w: '%s' in category '%s'", ErrUnknownProduct, i.ProductExternalCode, c.ExternalCode)
+			}
+			if linked[i.ProductExternalCode] {
+				return fmt.Errorf("%w: item '%s' in category '%s'",
+					ErrDuplicateExternalCode, i.ProductExternalCode, c.ExternalCode)
+			}
+			linked[i.ProductExternalCode] = true
+			ci := i.CategoryItem()
+			if err = ci.verify(); err != nil {
+				return fmt.Errorf("item '%s' in category '%s': %w", i.ProductExternalCode, c.ExternalCode, err)
+			}
+		}
+	}
+	return
+}
+
+func (c MenuCategory) template() string {
+	if c.Template == "" {
+		return "DEFAULT"
+	}
+	return c.Template
+}
+
+// CategoryItem link body of the item
+func (i MenuItem) CategoryItem() CategoryItem {
+	return CategoryItem{Status: i.Status, Price: i.Price, Sequence: i.Sequence, Shifts: i.Shifts}
+}
+
+// NewReconciler for the catalog of a merchant
+func NewReconciler(service Service, merchantID, catalogID string) *Reconciler {
+	return &Reconciler{
+		service:    service,
+		merchantID: merchantID,
+		catalogID:  catalogID,
+		Workers:    DefaultSnapshotWorkers,
+	}
+}
+
+// Plan compares the document with the live catalog, nothing is changed
+//
+// operations are ordered by dependency: products are created and updated
+// first, then categories, then items are linked and unlinked, and finally,
+// when DeleteMissing is set, categories and products are deleted
+func (r *Reconciler) Plan(md MenuDocument) (plan Plan, err error) {
+	if err = verifyCategoryItems(r.merchantID, r.catalogID, "category"); err != nil {
+		glg.Error("[SDK] Catalog Reconciler Plan: ", err.Error())
+		return
+	}
+	if err = md.Validate(); err != nil {
+		glg.Error("[SDK] Catalog Reconciler Plan Validate: ", err.Error())
+		return
+	}
+	products, categories, linkedProducts, err := r.live()
+	if err != nil {
+		glg.Error("[SDK] Catalog Reconciler Plan: ", err.Error(), " merchant: ", r.merchantID)
+		return
+	}
+	plan = Plan{
+		MerchantID:  r.merchantID,
+		CatalogID:   r.catalogID,
+		productIDs:  make(map[string]string),
+		categoryIDs: make(map[string]string),
+	}
+	for code, p := range products {
+		plan.productIDs[code] = p.ID
+	}
+	for code, c := range categories {
+		plan.categoryIDs[code] = c.ID
+	}
+	var creates, links, deletes []Operation
+	desiredProducts := make(map[string]bool)
+	for _, p := range md.Products {
+		desiredProducts[p.ExternalCode] = true
+		live, ok := products[p.ExternalCode]
+		if !ok {
+			creates = append(creates, Operation{Action: ActionCreate, Resource: ResourceProduct,
+				ExternalCode: p.ExternalCode, product: p})
+			continue
+		}
+		if changes := productChanges(live, p); len(changes) > 0 {
+			p.ID = live.ID
+			creates = append(creates, Operation{Action: ActionUpdate, Resource: ResourceProduct,
+				ExternalCode: p.ExternalCode, Changes: changes, id: live.ID, product: p})
+		}
+	}
+	desiredCategories := make(map[string]bool)
+	for _, c := range md.Categories {
+		desiredCategories[c.ExternalCode] = true
+		live, ok := categories[c.ExternalCode]
+		if !ok {
+			creates = append(creates, Operation{Action: ActionCreate, Resource: ResourceCategory,
+				ExternalCode: c.ExternalCode, category: c})
+			live = liveCategory{items: map[string]Item{}}
+		} else if changes := categoryChanges(live.CategoryResponse, c); len(changes) > 0 {
+			creates = append(creates, Operation{Action: ActionUpdate, Resource: ResourceCategory,
+				ExternalCode: c.ExternalCode, Changes: changes, id: live.ID, category: c})
+		}
+		linked := make(map[string]bool)
+		for _, i := range c.Items {
+			linked[i.ProductExternalCode] = true
+			item, ok := live.items[i.ProductExternalCode]
+			op := Operation{Action: ActionLink, Resource: ResourceItem,
+				ExternalCode: i.ProductExternalCode, Category: c.ExternalCode, item: i}
+			if ok {
+				if op.Changes = itemChanges(item, i); len(op.Changes) == 0 {
+					continue
+				}
+			}
+			links = append(links, op)
+		}
+		for code, item := range live.items {
+			if !linked[code] {
+				links = append(links, Operation{Action: ActionUnlink, Resource: ResourceItem,
+					ExternalCode: code, Category: c.ExternalCode, id: item.ProductID, categoryID: live.ID})
+			}
+		}
+	}
+	for code, c := range categories {
+		if r.DeleteMissing && !desiredCategories[code] {
+			deletes = append(deletes, Operation{Action: ActionDelete, Resource: ResourceCategory,
+				ExternalCode: code, id: c.ID})
+		}
+	}
+	for code, p := range products {
+		if r.DeleteMissing && !desiredProducts[code] && linkedProducts[code] {
+			deletes = append(deletes, Operation{Action: ActionDelete, Resource: ResourceProduct,
+				ExternalCode: code, id: p.ID})
+		}
+	}
+	sortOperations(creates, ResourceProduct, ResourceCategory)
+	sortOperations(links, ResourceItem)
+	// categories go before the products they may still reference
+	sortOperations(deletes, ResourceCategory, ResourceProduct)
+	plan.Operations = append(append(creates, links...), deletes...)
+	glg.Infof("[SDK] Catalog Reconciler Plan %d operations, merchant '%s'", len(plan.Operations), r.merchantID)
+	return
+}
+
+// Apply runs the plan operations in order
+//
+// operations depending on a failed create are reported with
+// ErrUnresolvedReference, planning again after a partial apply
+// only returns what is still missing
+func (r *Reconciler) Apply(plan Plan) (results []OperationResult) {
+	productIDs := make(map[string]string)
+	for code, id := range plan.productIDs {
+		productIDs[code] = id
+	}
+	categoryIDs := make(map[string]string)
+	for code, id := range plan.categoryIDs {
+		categoryIDs[code] = id
+	}
+	for _, op := range plan.Operations {
+		err := r.apply(op, productIDs, categoryIDs)
+		if err != nil {
+			glg.Errorf("[SDK] Catalog Reconciler Apply %s: %s", op, err.Error())
+		}
+		results = append(results, OperationResult{Operation: op, Err: err})
+	}
+	return
+}
+
+// Reconcile plans the document and, unless dryRun, applies it
+func (r *Reconciler) Reconcile(md MenuDocument, dryRun bool) (plan Plan, results []OperationResult, err error) {
+	if plan, err = r.Plan(md); err != nil || dryRun {
+		return
+	}
+	return plan, r.Apply(plan), nil
+}
+
+func (r *Reconciler) apply(op Operation, productIDs, categoryIDs map[string]string) (err error) {
+	switch {
+	case op.Resource == ResourceProduct && op.Action == ActionCreate:
+		created, err := r.service.CreateProduct(r.merchantID, op.product)
+		if err != nil {
+			return err
+		}
+		productIDs[op.ExternalCode] = created.ID
+	case op.Resource == ResourceProduct && op.Action == ActionUpdate:
+		_, err = r.service.EditProduct(r.merchantID, op.product)
+	case op.Resource == ResourceProduct && op.Action == ActionDelete:
+		err = r.service.DeleteProduct(r.merchantID, op.id)
+	case op.Resource == ResourceCategory && op.Action == ActionCreate:
+		c := op.category
+		created, err := r.service.CreateCategoryInCatalog(
+			r.merchantID, r.catalogID, c.Name, c.Status, c.template(), c.ExternalCode)
+		if err != nil {
+			return err
+		}
+		categoryIDs[op.ExternalCode] = created.ID
+		if c.Sequence != created.Sequence {
+			_, err = r.service.EditCategoryInCatalog(
+				r.merchantID, r.catalogID, created.ID, c.Name, c.Status, c.ExternalCode, c.Sequence)
+		}
+		return err
+	case op.Resource == ResourceCategory && op.Action == ActionUpdate:
+		c := op.category
+		_, err = r.service.EditCategoryInCatalog(
+			r.merchantID, r.catalogID, op.id, c.Name, c.Status, c.ExternalCode, c.Sequence)
+	case op.Resource == ResourceCategory && op.Action == ActionDelete:
+		err = r.service.DeleteCategoryInCatalog(r.merchantID, r.catalogID, op.id)
+	case op.Action == ActionLink:
+		productID, categoryID := productIDs[op.ExternalCode], categoryIDs[op.Category]
+		if productID == "" || categoryID == "" {
+			return fmt.Errorf("%w: item '%s' in category '%s'", ErrUnresolvedReference, op.ExternalCode, op.Category)
+		}
+		i := op.item
+		err = r.service.LinkProductToCategory(r.merchantID, categoryID, ProductLink{
+			ID:           productID,
+			ExternalCode: op.ExternalCode,
+			Status:       i.Status,
+			Price:        i.Price,
+			Sequence:     i.Sequence,
+			Shifts:       i.Shifts,
+		})
+	case op.Action == ActionUnlink:
+		err = r.service.UnlinkProductToCategory(r.merchantID, op.categoryID, op.id)
+	default:
+		err = fmt.Errorf("unknown operation %s", op)
+	}
+	return
+}
+
+// live reads the products and the catalog categories with their items,
+// indexed by external code, linked has the products with an item in the catalog
+func (r *Reconciler) live() (products map[string]Product, categories map[string]liveCategory, linked map[string]bool, err error) {
+	workers := r.Workers
+	if workers <= 0 {
+		workers = DefaultSnapshotWorkers
+	}
+	ps, cs, err := catalogContent(r.service, r.merchantID, r.catalogID, workers)
+	if err != nil {
+		return
+	}
+	products = make(map[string]Product)
+	codes := make(map[string]string)
+	for _, p := range ps {
+		codes[p.ID] = p.ExternalCode
+		if p.ExternalCode != "" {
+			products[p.ExternalCode] = p
+		}
+	}
+	categories = make(map[string]liveCategory)
+	linked = make(map[string]bool)
+	for _, c := range cs {
+		lc := liveCategory{CategoryResponse: c, items: make(map[string]Item)}
+		for _, item := range c.Items {
+			code, ok := codes[item.ProductID]
+			if !ok {
+				code = item.ExternalCode
+			}
+			if code != "" {
+				lc.items[code] = item
+				linked[code] = true
+			}
+		}
+		if c.ExternalCode != "" {
+			categories[c.ExternalCode] = lc
+		}
+	}
+	return
+}
+
+// catalogContent lists the products of a merchant and
+// every category of a catalog with its items
+func catalogContent(service Service, merchantID, catalogID string, workers int) (ps Products, cs Categories, err error) {
+	err = runBounded(workers, []func() error{
+		func() (err error) {
+			ps, err = service.ListProducts(merchantID)
+			return
+		},
+		func() (err error) {
+			cs, err = service.ListCategoriesInCatalog(merchantID, catalogID)
+			return
+		},
+	})
+	if err != nil {
+		return
+	}
+	tasks := make([]func() error, len(cs))
+	for i := range cs {
+		category := &cs[i]
+		tasks[i] = func() (err error) {
+			*category, err = service.GetCategoryInCatalog(merchantID, catalogID, category.ID)
+			return
+		}
+	}
+	err = runBounded(workers, tasks)
+	return
+}
+
+func productChanges(live, desired Product) (changes []string) {
+	if live.Name != desired.Name {
+		changes = append(changes, "name")
+	}
+	if live.Description != desired.Description {
+		changes = append(changes, "description")
+	}
+	if desired.Image != "" && live.Image != desired.Image {
+		changes = append(changes, "image")
+	}
+	if live.Serving != desired.Serving {
+		changes = append(changes, "serving")
+	}
+	if !sameStrings(live.DietaryRestrictions, desired.DietaryRestrictions) {
+		changes = append(changes, "dietaryRestrictions")
+	}
+	if live.Ean != desired.Ean {
+		changes = append(changes, "ean")
+	}
+	if !sameShifts(live.Shifts, desired.Shifts) {
+		changes = append(changes, "shifts")
+	}
+	return
+}
+
+func categoryChanges(live CategoryResponse, desired MenuCategory) (changes []string) {
+	if live.Name != desired.Name {
+		changes = append(changes, "name")
+	}
+	if live.Status != desired.Status {
+		changes = append(changes, "status")
+	}
+	if live.Sequence != desired.Sequence {
+		changes = append(changes, "sequence")
+	}
+	return
+}
+
+func itemChanges(live Item, desired MenuItem) (changes []string) {
+	if live.Status != desired.Status {
+		changes = append(changes, "status")
+	}
+	if live.Price != desired.Price {
+		changes = append(changes, "price")
+	}
+	if live.Sequence != desired.Sequence {
+		changes = append(changes, "sequence")
+	}
+	if !sameShifts(live.Shifts, desired.Shifts) {
+		changes = append(changes, "shifts")
+	}
+	return
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
+
+func sameShifts(a, b []Shift) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// sortOperations orders by resource, in the given order, then by external code
+func sortOperations(ops []Operation, order ...string) {
+	resources := make(map[string]int)
+	for i, resource := range order {
+		resources[resource] = i
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		a, b := ops[i], ops[j]
+		if a.Resource != b.Resource {
+			return resources[a.Resource] < resources[b.Resource]
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.ExternalCode < b.ExternalCode
+	})
+}
+
+// String describes the operation, as in "UPDATE PRODUCT 'x-burger' (name, shifts)"
+func (op Operation) String() string {
+	s := fmt.Sprintf("%s %s '%s'", op.Action, op.Resource, op.ExternalCode)
+	if op.Category != "" {
+		s += fmt.Sprintf(" in CATEGORY '%s'", op.Category)
+	}
+	if len(op.Changes) > 0 {
+		s += fmt.Sprintf(" (%s)", strings.Join(op.Changes, ", "))
+	}
+	return s
+}
+
+// String lists the plan operations, one per line, for dry runs
+func (p Plan) String() string {
+	if len(p.Operations) == 0 {
+		return fmt.Sprintf("catalog '%s' is up to date\n", p.CatalogID)
+	}
+	var b strings.Builder
+	for i, op := range p.Operations {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, op)
+	}
+	return b.String()
+}
+
+// Failed results with an error
+func Failed(results []OperationResult) (failed []OperationResult) {
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return
+}
@@ -0,0 +1,294 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var shifts = []Shift{{StartTime: "00:00", EndTime: "23:59", Monday: true}}
+
+// liveMenu in memory catalog keeping what the reconciler writes
+type liveMenu struct {
+	Service
+	mu         sync.Mutex
+	ids        int
+	products   map[string]Product
+	categories map[string]CategoryResponse
+	writes     []string
+	failCreate string
+}
+
+func newLiveMenu() *liveMenu {
+	m := &liveMenu{products: map[string]Product{}, categories: map[string]CategoryResponse{}}
+	m.products["id_old"] = Product{ID: "id_old", Name: "Old", ExternalCode: "p-old", Serving: "SERVES_1", Shifts: shifts}
+	m.products["id_burger"] = Product{ID: "id_burger", Name: "Burger", ExternalCode: "p-burger", Serving: "SERVES_1", Shifts: shifts}
+	m.products["id_manual"] = Product{ID: "id_manual", Name: "Manual"}
+	m.categories["cat_old"] = CategoryResponse{ID: "cat_old", Name: "Old", ExternalCode: "c-old", Status: "AVAILABLE"}
+	m.categories["cat_lanches"] = CategoryResponse{ID: "cat_lanches", Name: "Lanches", ExternalCode: "c-lanches",
+		Status: "AVAILABLE", Sequence: 1, Items: []Item{{ProductID: "id_old", Status: "AVAILABLE"}}}
+	return m
+}
+
+func (m *liveMenu) write(format string, args ...interface{}) {
+	m.writes = append(m.writes, fmt.Sprintf(format, args...))
+}
+
+func (m *liveMenu) ListProducts(merchantUUID string) (ps Products, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range m.products {
+		ps = append(ps, p)
+	}
+	return
+}
+
+func (m *liveMenu) ListCategoriesInCatalog(merchantUUID, catalogID string) (cs Categories, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.categories {
+		cs = append(cs, CategoryResponse{ID: c.ID})
+	}
+	return
+}
+
+func (m *liveMenu) GetCategoryInCatalog(merchantUUID, catalogID, categoryID string) (CategoryResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.categories[categoryID], nil
+}
+
+func (m *liveMenu) CreateProduct(merchantUUID string, product Product) (Product, error) {
+	if product.ExternalCode == m.failCreate {
+		return Product{}, errors.New("create failed")
+	}
+	m.ids++
+	product.ID = fmt.Sprintf("id_%d", m.ids)
+	m.products[product.ID] = product
+	m.write("create product %s", product.ExternalCode)
+	return product, nil
+}
+
+func (m *liveMenu) EditProduct(merchantUUID string, product Product) (Product, error) {
+	m.products[product.ID] = product
+	m.write("edit product %s", product.ID)
+	return product, nil
+}
+
+func (m *liveMenu) DeleteProduct(merchantUUID, productID string) error {
+	delete(m.products, productID)
+	m.write("delete product %s", productID)
+	return nil
+}
+
+func (m *liveMenu) CreateCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, template, externalCode string) (CategoryCreateResponse, error) {
+	m.ids++
+	id := fmt.Sprintf("cat_%d", m.ids)
+	m.categories[id] = CategoryResponse{ID: id, Name: name, Status: resourceStatus, ExternalCode: externalCode, Template: template}
+	m.write("create category %s", externalCode)
+	return CategoryCreateResponse{ID: id, Name: name}, nil
+}
+
+func (m *liveMenu) EditCategoryInCatalog(merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode string, sequence int) (CategoryCreateResponse, error) {
+	c := m.categories[categoryID]
+	c.Name, c.Status, c.ExternalCode, c.Sequence = name, resourceStatus, externalCode, sequence
+	m.categories[categoryID] = c
+	m.write("edit category %s", categoryID)
+	return CategoryCreateResponse{ID: categoryID}, nil
+}
+
+func (m *liveMenu) DeleteCategoryInCatalog(merchantUUID, catalogID, categoryID string) error {
+	delete(m.categories, categoryID)
+	m.write("delete category %s", categoryID)
+	return nil
+}
+
+func (m *liveMenu) LinkProductToCategory(merchantUUID, categoryID string, product ProductLink) error {
+	c := m.categories[categoryID]
+	items := []Item{}
+	for _, i := range c.Items {
+		if i.ProductID != product.ID {
+			items = append(items, i)
+		}
+	}
+	c.Items = append(items, Item{ProductID: product.ID, Status: product.Status, Price: product.Price,
+		Sequence: product.Sequence, Shifts: product.Shifts})
+	m.categories[categoryID] = c
+	m.write("link %s %s", categoryID, product.ID)
+	return nil
+}
+
+func (m *liveMenu) UnlinkProductToCategory(merchantUUID, categoryID, productID string) error {
+	c := m.categories[categoryID]
+	items := []Item{}
+	for _, i := range c.Items {
+		if i.ProductID != productID {
+			items = append(items, i)
+		}
+	}
+	c.Items = items
+	m.categories[categoryID] = c
+	m.write("unlink %s %s", categoryID, productID)
+	return nil
+}
+
+var menuYAML = `
+products:
+  - externalCode: p-burger
+    name: X-Burger
+    serving: SERVES_1
+    shifts: [{startTime: "00:00", endTime: "23:59", monday: true}]
+  - externalCode: p-soda
+    name: Soda
+    serving: SERVES_1
+    dietaryRestrictions: [VEGAN]
+    shifts: [{startTime: "00:00", endTime: "23:59", monday: true}]
+categories:
+  - externalCode: c-lanches
+    name: Lanches
+    status: AVAILABLE
+    sequence: 1
+    items:
+      - productExternalCode: p-burger
+        status: AVAILABLE
+        price: {value: 25.9}
+        shifts: [{startTime: "00:00", endTime: "23:59", monday: true}]
+  - externalCode: c-drinks
+    name: Bebidas
+    status: AVAILABLE
+    sequence: 2
+    items:
+      - productExternalCode: p-soda
+        status: AVAILABLE
+        price: {value: 6, originalValue: 8}
+        shifts: [{startTime: "00:00", endTime: "23:59", monday: true}]
+`
+
+func TestReadMenuDocument(t *testing.T) {
+	md, err := ReadMenuDocument([]byte(menuYAML))
+	assert.Nil(t, err)
+	assert.Equal(t, "p-soda", md.Products[1].ExternalCode)
+	assert.Equal(t, []string{"VEGAN"}, md.Products[1].DietaryRestrictions)
+	assert.Equal(t, "00:00", md.Categories[0].Items[0].Shifts[0].StartTime)
+	assert.Equal(t, Price{Value: 6, OriginalValue: 8}, md.Categories[1].Items[0].Price)
+	assert.Nil(t, md.Validate())
+
+	md, err = ReadMenuDocument([]byte(`{"products": [{"externalCode": "p-1", "name": "Json"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "Json", md.Products[0].Name)
+}
+
+func TestMenuDocument_Validate(t *testing.T) {
+	md, _ := ReadMenuDocument([]byte(menuYAML))
+	md.Products = append(md.Products, md.Products[0])
+	assert.True(t, errors.Is(md.Validate(), ErrDuplicateExternalCode))
+
+	md, _ = ReadMenuDocument([]byte(menuYAML))
+	md.Products = md.Products[:1]
+	assert.True(t, errors.Is(md.Validate(), ErrUnknownProduct))
+
+	md, _ = ReadMenuDocument([]byte(menuYAML))
+	md.Categories[0].ExternalCode = ""
+	assert.True(t, errors.Is(md.Validate(), ErrNoExternalCode))
+
+	md, _ = ReadMenuDocument([]byte(menuYAML))
+	md.Categories[0].Items[0].Price = Price{}
+	assert.True(t, errors.Is(md.Validate(), ErrNoPrice))
+}
+
+func TestReconciler_PlanApply(t *testing.T) {
+	md, _ := ReadMenuDocument([]byte(menuYAML))
+	live := newLiveMenu()
+	r := NewReconciler(live, "merchant_id", "catalog_id")
+	r.DeleteMissing = true
+
+	plan, results, err := r.Reconcile(md, true)
+	assert.Nil(t, err)
+	assert.Nil(t, results)
+	assert.Empty(t, live.writes)
+	assert.Equal(t, `1. UPDATE PRODUCT 'p-burger' (name)
+2. CREATE PRODUCT 'p-soda'
+3. CREATE CATEGORY 'c-drinks'
+4. LINK ITEM 'p-soda' in CATEGORY 'c-drinks'
+5. LINK ITEM 'p-burger' in CATEGORY 'c-lanches'
+6. UNLINK ITEM 'p-old' in CATEGORY 'c-lanches'
+7. DELETE CATEGORY 'c-old'
+8. DELETE PRODUCT 'p-old'
+`, plan.String())
+
+	results = r.Apply(plan)
+	assert.Equal(t, 8, len(results))
+	assert.Empty(t, Failed(results))
+	assert.Equal(t, []string{
+		"edit product id_burger",
+		"create product p-soda",
+		"create category c-drinks",
+		"edit category cat_2",
+		"link cat_2 id_1",
+		"link cat_lanches id_burger",
+		"unlink cat_lanches id_old",
+		"delete category cat_old",
+		"delete product id_old",
+	}, live.writes)
+	_, ok := live.products["id_manual"]
+	assert.True(t, ok)
+
+	plan, err = r.Plan(md)
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Operations)
+	assert.Equal(t, "catalog 'catalog_id' is up to date\n", plan.String())
+}
+
+func TestReconciler_ApplyFailedDependency(t *testing.T) {
+	md, _ := ReadMenuDocument([]byte(menuYAML))
+	live := newLiveMenu()
+	live.failCreate = "p-soda"
+	r := NewReconciler(live, "merchant_id", "catalog_id")
+	_, results, err := r.Reconcile(md, false)
+	assert.Nil(t, err)
+	failed := Failed(results)
+	assert.Equal(t, 2, len(failed))
+	assert.EqualError(t, failed[0].Err, "create failed")
+	assert.True(t, errors.Is(failed[1].Err, ErrUnresolvedReference))
+	assert.Equal(t, "c-drinks", failed[1].Category)
+
+	// re-running only retries what is missing
+	live.failCreate = ""
+	plan, err := r.Plan(md)
+	assert.Nil(t, err)
+	assert.Equal(t, "1. CREATE PRODUCT 'p-soda'\n2. LINK ITEM 'p-soda' in CATEGORY 'c-drinks'\n", plan.String())
+	assert.Empty(t, Failed(r.Apply(plan)))
+}
+
+func TestReconciler_DeleteMissing(t *testing.T) {
+	md, _ := ReadMenuDocument([]byte(menuYAML))
+	live := newLiveMenu()
+	// p-other is only linked in another catalog of the merchant
+	live.products["id_other"] = Product{ID: "id_other", Name: "Other", ExternalCode: "p-other", Serving: "SERVES_1", Shifts: shifts}
+	r := NewReconciler(live, "merchant_id", "catalog_id")
+	plan, err := r.Plan(md)
+	assert.Nil(t, err)
+	assert.NotContains(t, plan.String(), "DELETE")
+
+	r.DeleteMissing = true
+	plan, err = r.Plan(md)
+	assert.Nil(t, err)
+	assert.Contains(t, plan.String(), "DELETE CATEGORY 'c-old'")
+	assert.Contains(t, plan.String(), "DELETE PRODUCT 'p-old'")
+	assert.NotContains(t, plan.String(), "p-other")
+	assert.Empty(t, Failed(r.Apply(plan)))
+	_, ok := live.products["id_other"]
+	assert.True(t, ok)
+	_, ok = live.products["id_old"]
+	assert.False(t, ok)
+}
+
+func TestReconciler_PlanErrors(t *testing.T) {
+	_, err := NewReconciler(newLiveMenu(), "merchant_id", "").Plan(MenuDocument{})
+	assert.Equal(t, ErrCatalogNotSpecified, err)
+	_, err = NewReconciler(newLiveMenu(), "merchant_id", "catalog_id").Plan(MenuDocument{Products: []Product{{}}})
+	assert.True(t, errors.Is(err, ErrNoExternalCode))
+}
@@ -0,0 +1,309 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kpango/glg"
+)
+
+// DefaultReplicationWorkers targets replicated at the same time
+const DefaultReplicationWorkers = 4
+
+type (
+	// ReplicationTarget catalog of a merchant that receives the source menu
+	ReplicationTarget struct {
+		MerchantID string `json:"merchantId"`
+		CatalogID  string `json:"catalogId"`
+		// Prices overrides the item prices of the products with the external code
+		Prices map[string]Price `json:"prices,omitempty"`
+	}
+
+	// ReplicationResult outcome of a target
+	ReplicationResult struct {
+		Target ReplicationTarget
+		// Skipped the checkpoint has the target as done with the same document
+		Skipped bool
+		Plan    Plan
+		Results []OperationResult
+		Err     error
+	}
+
+	// ReplicationCheckpoint targets already replicated, by merchant and catalog
+	ReplicationCheckpoint struct {
+		Done map[string]ReplicatedTarget `json:"done"`
+	}
+
+	// ReplicatedTarget document a target received, a change in the source
+	// or in its price overrides replicates the target again
+	ReplicatedTarget struct {
+		Document string    `json:"document"`
+		At       time.Time `json:"at"`
+	}
+
+	// Replicator copies the catalog of a source merchant to other merchants
+	Replicator struct {
+		service Service
+		source  MenuDocument
+		// Workers targets replicated at the same time
+		Workers int
+		// CheckpointFile keeps the targets done, so a run after a
+		// failure only replicates what is missing, empty disables it
+		CheckpointFile string
+		// Prune deletes the categories of a target missing from the source,
+		// and the products linked in them, see Reconciler.DeleteMissing
+		Prune      bool
+		mu         sync.Mutex
+		checkpoint ReplicationCheckpoint
+	}
+)
+
+// MenuDocument of a catalog of the snapshot, products and categories
+// without external code are left out, as are items of those products and
+// products not linked in the catalog. PIZZA categories are left out too,
+// menu documents do not carry pizzas
+func (ms MenuSnapshot) MenuDocument(catalogID string) (md MenuDocument, err error) {
+	var catalog *CatalogSnapshot
+	for i := range ms.Catalogs {
+		if ms.Catalogs[i].ID == catalogID {
+			catalog = &ms.Catalogs[i]
+		}
+	}
+	if catalog == nil {
+		err = fmt.Errorf("catalog '%s' is not in the snapshot of merchant '%s'", catalogID, ms.MerchantID)
+		return
+	}
+	codes := make(map[string]string)
+	for _, p := range ms.Products {
+		if p.ExternalCode != "" {
+			codes[p.ID] = p.ExternalCode
+		}
+	}
+	linked := make(map[string]bool)
+	for _, c := range catalog.Categories {
+		if c.ExternalCode == "" {
+			continue
+		}
+		if c.Template == "PIZZA" {
+			glg.Warnf("[SDK] Catalog MenuDocument PIZZA category '%s' is not supported, left out", c.ExternalCode)
+			continue
+		}
+		mc := MenuCategory{Name: c.Name, ExternalCode: c.ExternalCode, Status: c.Status,
+			Template: c.Template, Sequence: c.Sequence}
+		for _, item := range c.Items {
+			code, ok := codes[item.ProductID]
+			if !ok {
+				continue
+			}
+			linked[item.ProductID] = true
+			mc.Items = append(mc.Items, MenuItem{ProductExternalCode: code, Status: item.Status,
+				Price: item.Price, Sequence: item.Sequence, Shifts: item.Shifts})
+		}
+		md.Categories = append(md.Categories, mc)
+	}
+	for _, p := range ms.Products {
+		if !linked[p.ID] {
+			continue
+		}
+		p.ID = ""
+		md.Products = append(md.Products, p)
+	}
+	return
+}
+
+// NewReplicator copies the source menu document, as
+// built by MenuSnapshot.MenuDocument, to the targets
+func NewReplicator(service Service, source MenuDocument) *Replicator {
+	return &Replicator{service: service, source: source, Workers: DefaultReplicationWorkers}
+}
+
+// NewReplicatorFromMerchant reads the source catalog of a merchant,
+// only the products linked in it are replicated
+func NewReplicatorFromMerchant(service Service, merchantID, catalogID string) (r *Replicator, err error) {
+	if err = verifyCategoryItems(merchantID, catalogID, "category"); err != nil {
+		glg.Error("[SDK] Catalog NewReplicatorFromMerchant: ", err.Error())
+		return
+	}
+	ps, cs, err := catalogContent(service, merchantID, catalogID, DefaultSnapshotWorkers)
+	if err != nil {
+		glg.Error("[SDK] Catalog NewReplicatorFromMerchant: ", err.Error(), " merchant: ", merchantID)
+		return
+	}
+	ms := MenuSnapshot{
+		MerchantID: merchantID,
+		Catalogs:   []CatalogSnapshot{{Catalog: Catalog{ID: catalogID}, Categories: cs}},
+		Products:   ps,
+	}
+	md, err := ms.MenuDocument(catalogID)
+	if err != nil {
+		return
+	}
+	return NewReplicator(service, md), nil
+}
+
+// Replicate reconciles every target with the source menu, items missing
+// from a source category are unlinked, categories and products missing
+// from the source are only deleted with Prune
+//
+// a target is done when all of its operations succeed, err is only set
+// when the source is invalid or the checkpoint can not be read
+func (r *Replicator) Replicate(targets []ReplicationTarget) (results []ReplicationResult, err error) {
+	if err = r.source.Validate(); err != nil {
+		glg.Error("[SDK] Catalog Replicator Replicate Validate: ", err.Error())
+		return
+	}
+	if err = r.loadCheckpoint(); err != nil {
+		glg.Error("[SDK] Catalog Replicator Replicate loadCheckpoint: ", err.Error())
+		return
+	}
+	workers := r.Workers
+	if workers <= 0 {
+		workers = DefaultReplicationWorkers
+	}
+	results = make([]ReplicationResult, len(targets))
+	tasks := make([]func() error, len(targets))
+	for i := range targets {
+		res := &results[i]
+		res.Target = targets[i]
+		tasks[i] = func() error {
+			r.replicate(res)
+			return nil
+		}
+	}
+	runBounded(workers, tasks)
+	return
+}
+
+func (r *Replicator) replicate(res *ReplicationResult) {
+	md, err := r.document(res.Target)
+	if err != nil {
+		res.Err = err
+		glg.Error("[SDK] Catalog Replicator merchant '", res.Target.MerchantID, "': ", err.Error())
+		return
+	}
+	document, err := documentHash(md)
+	if err != nil {
+		res.Err = err
+		glg.Error("[SDK] Catalog Replicator merchant '", res.Target.MerchantID, "': ", err.Error())
+		return
+	}
+	key := res.Target.MerchantID + "/" + res.Target.CatalogID
+	r.mu.Lock()
+	done, ok := r.checkpoint.Done[key]
+	r.mu.Unlock()
+	if res.Skipped = ok && (done.Document == document); res.Skipped {
+		return
+	}
+	reconciler := NewReconciler(r.service, res.Target.MerchantID, res.Target.CatalogID)
+	reconciler.DeleteMissing = r.Prune
+	if res.Plan, res.Err = reconciler.Plan(md); res.Err == nil {
+		res.Results = reconciler.Apply(res.Plan)
+	}
+	if failed := Failed(res.Results); (res.Err == nil) && (len(failed) > 0) {
+		res.Err = fmt.Errorf("%d of %d operations failed, first: %w", len(failed), len(res.Results), failed[0].Err)
+	}
+	if res.Err != nil {
+		glg.Error("[SDK] Catalog Replicator merchant '", res.Target.MerchantID, "': ", res.Err.Error())
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkpoint.Done[key] = ReplicatedTarget{Document: document, At: time.Now().UTC()}
+	if err = r.saveCheckpoint(); err != nil {
+		glg.Error("[SDK] Catalog Replicator saveCheckpoint: ", err.Error())
+	}
+	glg.Infof("[SDK] Catalog Replicator merchant '%s' done, %d operations", res.Target.MerchantID, len(res.Results))
+}
+
+// document of a target, the source with its price overrides
+func (r *Replicator) document(target ReplicationTarget) (md MenuDocument, err error) {
+	products := make(map[string]bool)
+	for _, p := range r.source.Products {
+		products[p.ExternalCode] = true
+	}
+	for code, price := range target.Prices {
+		if !products[code] {
+			return md, fmt.Errorf("%w: price override '%s'", ErrUnknownProduct, code)
+		}
+		u := PriceUpdate{ExternalCode: code, Value: price.Value, OriginalValue: price.OriginalValue}
+		if err = u.verify(); err != nil {
+			return md, fmt.Errorf("price override '%s': %w", code, err)
+		}
+	}
+	md.Products = r.source.Products
+	md.Categories = make([]MenuCategory, len(r.source.Categories))
+	for i, c := range r.source.Categories {
+		c.Items = append([]MenuItem(nil), c.Items...)
+		for j := range c.Items {
+			if price, ok := target.Prices[c.Items[j].ProductExternalCode]; ok {
+				c.Items[j].Price = price
+			}
+		}
+		md.Categories[i] = c
+	}
+	return
+}
+
+// documentHash identifies the document of a target in the checkpoint
+func documentHash(md MenuDocument) (string, error) {
+	data, err := json.Marshal(md)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (r *Replicator) loadCheckpoint() (err error) {
+	r.checkpoint = ReplicationCheckpoint{Done: make(map[string]ReplicatedTarget)}
+	if r.CheckpointFile == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(r.CheckpointFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return
+	}
+	var stored ReplicationCheckpoint
+	if err = json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("checkpoint file '%s': %w", r.CheckpointFile, err)
+	}
+	if stored.Done != nil {
+		r.checkpoint = stored
+	}
+	return
+}
+
+// saveCheckpoint writes to a temporary file first, so
+// an interrupted run never leaves a partial checkpoint
+func (r *Replicator) saveCheckpoint() (err error) {
+	if r.CheckpointFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(r.checkpoint, "", "  ")
+	if err != nil {
+		return
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(r.CheckpointFile), filepath.Base(r.CheckpointFile)+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		return
+	}
+	return os.Rename(tmp.Name(), r.CheckpointFile)
+}
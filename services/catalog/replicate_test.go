@@ -0,0 +1,269 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// franchise routes every call to the liveMenu of its merchant
+type franchise struct {
+	Service
+	mu     sync.Mutex
+	stores map[string]*liveMenu
+}
+
+func newFranchise(merchantIDs ...string) *franchise {
+	f := &franchise{stores: map[string]*liveMenu{}}
+	for _, id := range merchantIDs {
+		f.stores[id] = &liveMenu{products: map[string]Product{}, categories: map[string]CategoryResponse{}}
+	}
+	return f
+}
+
+func (f *franchise) store(merchantID string) *liveMenu {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stores[merchantID]
+}
+
+func (f *franchise) ListProducts(merchantUUID string) (Products, error) {
+	return f.store(merchantUUID).ListProducts(merchantUUID)
+}
+
+func (f *franchise) ListCategoriesInCatalog(merchantUUID, catalogID string) (Categories, error) {
+	return f.store(merchantUUID).ListCategoriesInCatalog(merchantUUID, catalogID)
+}
+
+func (f *franchise) GetCategoryInCatalog(merchantUUID, catalogID, categoryID string) (CategoryResponse, error) {
+	return f.store(merchantUUID).GetCategoryInCatalog(merchantUUID, catalogID, categoryID)
+}
+
+func (f *franchise) CreateProduct(merchantUUID string, product Product) (Product, error) {
+	return f.store(merchantUUID).CreateProduct(merchantUUID, product)
+}
+
+func (f *franchise) EditProduct(merchantUUID string, product Product) (Product, error) {
+	return f.store(merchantUUID).EditProduct(merchantUUID, product)
+}
+
+func (f *franchise) DeleteProduct(merchantUUID, productID string) error {
+	return f.store(merchantUUID).DeleteProduct(merchantUUID, productID)
+}
+
+func (f *franchise) CreateCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, template, externalCode string) (CategoryCreateResponse, error) {
+	return f.store(merchantUUID).CreateCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, template, externalCode)
+}
+
+func (f *franchise) EditCategoryInCatalog(merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode string, sequence int) (CategoryCreateResponse, error) {
+	return f.store(merchantUUID).EditCategoryInCatalog(merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode, sequence)
+}
+
+func (f *franchise) DeleteCategoryInCatalog(merchantUUID, catalogID, categoryID string) error {
+	return f.store(merchantUUID).DeleteCategoryInCatalog(merchantUUID, catalogID, categoryID)
+}
+
+func (f *franchise) LinkProductToCategory(merchantUUID, categoryID string, product ProductLink) error {
+	return f.store(merchantUUID).LinkProductToCategory(merchantUUID, categoryID, product)
+}
+
+func (f *franchise) UnlinkProductToCategory(merchantUUID, categoryID, productID string) error {
+	return f.store(merchantUUID).UnlinkProductToCategory(merchantUUID, categoryID, productID)
+}
+
+// itemPrices by category and product external code
+func itemPrices(m *liveMenu) map[string]Price {
+	prices := map[string]Price{}
+	for _, c := range m.categories {
+		for _, item := range c.Items {
+			prices[c.ExternalCode+"/"+m.products[item.ProductID].ExternalCode] = item.Price
+		}
+	}
+	return prices
+}
+
+func TestMenuSnapshot_MenuDocument(t *testing.T) {
+	ms := MenuSnapshot{
+		MerchantID: "merchant_id",
+		Catalogs: []CatalogSnapshot{{Catalog: Catalog{ID: "catalog_id"}, Categories: Categories{
+			{ID: "cat_1", Name: "Lanches", ExternalCode: "c-lanches", Status: "AVAILABLE", Template: "DEFAULT", Items: []Item{
+				{ProductID: "id_burger", Status: "AVAILABLE", Price: Price{Value: 20}, Sequence: 1, Shifts: shifts},
+				{ProductID: "id_manual", Status: "AVAILABLE", Price: Price{Value: 5}, Shifts: shifts},
+			}},
+			{ID: "cat_2", Name: "Manual"},
+			{ID: "cat_3", Name: "Pizzas", ExternalCode: "c-pizza", Template: "PIZZA", Pizza: Pizza{ID: "pizza_id"}},
+		}}},
+		Products: Products{
+			{ID: "id_burger", Name: "Burger", ExternalCode: "p-burger", Serving: "SERVES_1", Shifts: shifts},
+			{ID: "id_manual", Name: "Manual"},
+			{ID: "id_other", Name: "Other", ExternalCode: "p-other"},
+		},
+	}
+	md, err := ms.MenuDocument("catalog_id")
+	assert.Nil(t, err)
+	assert.Equal(t, MenuDocument{
+		Products: []Product{{Name: "Burger", ExternalCode: "p-burger", Serving: "SERVES_1", Shifts: shifts}},
+		Categories: []MenuCategory{{Name: "Lanches", ExternalCode: "c-lanches", Status: "AVAILABLE", Template: "DEFAULT",
+			Items: []MenuItem{{ProductExternalCode: "p-burger", Status: "AVAILABLE", Price: Price{Value: 20}, Sequence: 1, Shifts: shifts}}}},
+	}, md)
+	_, err = ms.MenuDocument("other")
+	assert.EqualError(t, err, "catalog 'other' is not in the snapshot of merchant 'merchant_id'")
+}
+
+func TestReplicate_Checkpoint(t *testing.T) {
+	source, _ := ReadMenuDocument([]byte(menuYAML))
+	f := newFranchise("store_1", "store_2", "store_3")
+	f.stores["store_1"] = newLiveMenu()
+	f.stores["store_2"].failCreate = "p-soda"
+	dir, _ := ioutil.TempDir("", "replicate")
+	defer os.RemoveAll(dir)
+	checkpoint := filepath.Join(dir, "checkpoint.json")
+	targets := []ReplicationTarget{
+		{MerchantID: "store_1", CatalogID: "catalog_id", Prices: map[string]Price{"p-burger": {Value: 27.9}}},
+		{MerchantID: "store_2", CatalogID: "catalog_id"},
+		{MerchantID: "store_3", CatalogID: "catalog_id"},
+	}
+
+	r := NewReplicator(f, source)
+	r.CheckpointFile = checkpoint
+	results, err := r.Replicate(targets)
+	assert.Nil(t, err)
+	assert.Nil(t, results[0].Err)
+	assert.Contains(t, results[1].Err.Error(), "create failed")
+	assert.Nil(t, results[2].Err)
+	assert.Equal(t, map[string]Price{
+		"c-lanches/p-burger": {Value: 27.9},
+		"c-drinks/p-soda":    {Value: 6, OriginalValue: 8},
+	}, itemPrices(f.stores["store_1"]))
+	assert.Equal(t, Price{Value: 25.9}, itemPrices(f.stores["store_3"])["c-lanches/p-burger"])
+	// entries of store_1 missing from the source are kept
+	_, ok := f.stores["store_1"].products["id_old"]
+	assert.True(t, ok)
+	_, ok = f.stores["store_1"].categories["cat_old"]
+	assert.True(t, ok)
+	data, _ := ioutil.ReadFile(checkpoint)
+	stored := ReplicationCheckpoint{}
+	assert.Nil(t, json.Unmarshal(data, &stored))
+	assert.Equal(t, 2, len(stored.Done))
+	assert.Contains(t, stored.Done, "store_3/catalog_id")
+
+	// a second run only replicates what failed
+	f.stores["store_2"].failCreate = ""
+	r = NewReplicator(f, source)
+	r.CheckpointFile = checkpoint
+	results, err = r.Replicate(targets)
+	assert.Nil(t, err)
+	assert.True(t, results[0].Skipped)
+	assert.False(t, results[1].Skipped)
+	assert.Nil(t, results[1].Err)
+	assert.True(t, results[2].Skipped)
+	assert.Equal(t, 2, len(itemPrices(f.stores["store_2"])))
+
+	// changed price overrides replicate only that target again
+	targets[0].Prices = map[string]Price{"p-burger": {Value: 28.9}}
+	r = NewReplicator(f, source)
+	r.CheckpointFile = checkpoint
+	results, err = r.Replicate(targets)
+	assert.Nil(t, err)
+	assert.False(t, results[0].Skipped)
+	assert.Nil(t, results[0].Err)
+	assert.True(t, results[1].Skipped)
+	assert.True(t, results[2].Skipped)
+	assert.Equal(t, Price{Value: 28.9}, itemPrices(f.stores["store_1"])["c-lanches/p-burger"])
+
+	// a changed source replicates every target it changes again
+	source.Categories[1].Items[0].Price = Price{Value: 7}
+	r = NewReplicator(f, source)
+	r.CheckpointFile = checkpoint
+	results, err = r.Replicate(targets)
+	assert.Nil(t, err)
+	for _, res := range results {
+		assert.False(t, res.Skipped)
+		assert.Nil(t, res.Err)
+	}
+	assert.Equal(t, Price{Value: 7}, itemPrices(f.stores["store_3"])["c-drinks/p-soda"])
+	assert.Equal(t, Price{Value: 7}, itemPrices(f.stores["store_1"])["c-drinks/p-soda"])
+	assert.Equal(t, Price{Value: 28.9}, itemPrices(f.stores["store_1"])["c-lanches/p-burger"])
+
+	// an overridden price of the source leaves the target as done
+	source.Categories[0].Items[0].Price = Price{Value: 26.9}
+	r = NewReplicator(f, source)
+	r.CheckpointFile = checkpoint
+	results, err = r.Replicate(targets)
+	assert.Nil(t, err)
+	assert.True(t, results[0].Skipped)
+	assert.False(t, results[2].Skipped)
+	assert.Equal(t, Price{Value: 26.9}, itemPrices(f.stores["store_3"])["c-lanches/p-burger"])
+}
+
+func TestReplicate_Prune(t *testing.T) {
+	source, _ := ReadMenuDocument([]byte(menuYAML))
+	f := newFranchise()
+	f.stores["store_1"] = newLiveMenu()
+	r := NewReplicator(f, source)
+	r.Prune = true
+	results, err := r.Replicate([]ReplicationTarget{{MerchantID: "store_1", CatalogID: "catalog_id"}})
+	assert.Nil(t, err)
+	assert.Nil(t, results[0].Err)
+	_, ok := f.stores["store_1"].categories["cat_old"]
+	assert.False(t, ok)
+	_, ok = f.stores["store_1"].products["id_old"]
+	assert.False(t, ok)
+	_, ok = f.stores["store_1"].products["id_manual"]
+	assert.True(t, ok)
+}
+
+func TestReplicate_Errors(t *testing.T) {
+	source, _ := ReadMenuDocument([]byte(menuYAML))
+	f := newFranchise("store_1")
+	results, err := NewReplicator(f, source).Replicate([]ReplicationTarget{
+		{MerchantID: "store_1", CatalogID: "catalog_id", Prices: map[string]Price{"p-pizza": {Value: 40}}},
+		{MerchantID: "store_1", CatalogID: "catalog_id", Prices: map[string]Price{"p-soda": {Value: 6, OriginalValue: 5}}},
+		{MerchantID: "", CatalogID: "catalog_id"},
+	})
+	assert.Nil(t, err)
+	assert.True(t, errors.Is(results[0].Err, ErrUnknownProduct))
+	assert.True(t, errors.Is(results[1].Err, ErrInvalidPromotionPrice))
+	assert.Equal(t, ErrMerchantNotSpecified, results[2].Err)
+	assert.Equal(t, 0, len(f.stores["store_1"].writes))
+
+	invalid := source
+	invalid.Products = nil
+	_, err = NewReplicator(f, invalid).Replicate(nil)
+	assert.True(t, errors.Is(err, ErrUnknownProduct))
+
+	r := NewReplicator(f, source)
+	r.CheckpointFile = filepath.Join(os.TempDir(), "replicate-invalid.json")
+	ioutil.WriteFile(r.CheckpointFile, []byte("{"), 0600)
+	defer os.Remove(r.CheckpointFile)
+	_, err = r.Replicate(nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewReplicatorFromMerchant(t *testing.T) {
+	f := newFranchise("store_1")
+	f.stores["master"] = newLiveMenu()
+	f.stores["master"].categories["cat_lanches"] = CategoryResponse{ID: "cat_lanches", Name: "Lanches",
+		ExternalCode: "c-lanches", Status: "AVAILABLE", Items: []Item{
+			{ProductID: "id_burger", Status: "AVAILABLE", Price: Price{Value: 20}, Shifts: shifts},
+			{ProductID: "id_manual", Status: "AVAILABLE", Price: Price{Value: 5}, Shifts: shifts},
+		}}
+	delete(f.stores["master"].categories, "cat_old")
+	r, err := NewReplicatorFromMerchant(f, "master", "catalog_id")
+	assert.Nil(t, err)
+	results, err := r.Replicate([]ReplicationTarget{{MerchantID: "store_1", CatalogID: "catalog_id"}})
+	assert.Nil(t, err)
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, map[string]Price{"c-lanches/p-burger": {Value: 20}}, itemPrices(f.stores["store_1"]))
+	// p-old is not linked in the source catalog
+	assert.Equal(t, 1, len(f.stores["store_1"].products))
+
+	_, err = NewReplicatorFromMerchant(f, "", "catalog_id")
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+}
@@ -0,0 +1,209 @@
+package catalog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kpango/glg"
+)
+
+// DefaultRepriceInterval minimum time between two item updates of a Repricer
+const DefaultRepriceInterval = 200 * time.Millisecond
+
+type (
+	// PriceUpdate new price of a product, found by its external code
+	// or id, an OriginalValue above Value shows it as a promotion
+	PriceUpdate struct {
+		ExternalCode  string  `json:"externalCode,omitempty"`
+		ProductID     string  `json:"productId,omitempty"`
+		Value         float64 `json:"value"`
+		OriginalValue float64 `json:"originalValue"`
+	}
+
+	// PriceResult outcome of a PriceUpdate on one item, a product
+	// linked to several categories has a result for each of them
+	PriceResult struct {
+		PriceUpdate
+		CategoryID string
+		// Previous price of the item
+		Previous Price
+		// Unchanged the item already had the new price
+		Unchanged bool
+		Err       error
+	}
+
+	// Repricer updates the item prices of a catalog
+	Repricer struct {
+		service    Service
+		merchantID string
+		catalogID  string
+		// Workers concurrent requests
+		Workers int
+		// Interval minimum time between two item updates
+		Interval time.Duration
+		// Progress is called after each item update with
+		// the number of updates done and the total
+		Progress func(done, total int)
+	}
+
+	itemLink struct {
+		categoryID string
+		item       Item
+	}
+
+	// pendingPrice item of results[index] waiting for its update
+	pendingPrice struct {
+		index int
+		item  Item
+	}
+)
+
+func (u PriceUpdate) verify() error {
+	if (u.ExternalCode == "") && (u.ProductID == "") {
+		return ErrNoPriceTarget
+	}
+	if u.Value <= 0 {
+		return ErrNoItemPrice
+	}
+	if (u.OriginalValue != 0) && (u.OriginalValue < u.Value) {
+		return fmt.Errorf("%w: original value %.2f, value %.2f", ErrInvalidPromotionPrice, u.OriginalValue, u.Value)
+	}
+	return nil
+}
+
+func (u PriceUpdate) price() Price {
+	return Price{Value: u.Value, OriginalValue: u.OriginalValue}
+}
+
+// NewRepricer for the catalog of a merchant
+func NewRepricer(service Service, merchantID, catalogID string) *Repricer {
+	return &Repricer{
+		service:    service,
+		merchantID: merchantID,
+		catalogID:  catalogID,
+		Workers:    DefaultSnapshotWorkers,
+		Interval:   DefaultRepriceInterval,
+	}
+}
+
+// Reprice resolves every update to the items of its product and edits
+// their price, keeping status, sequence and shifts
+//
+// updates that are invalid or match no item are reported in their
+// result and the others are still applied, err is only set when
+// the catalog can not be read
+func (r *Repricer) Reprice(updates []PriceUpdate) (results []PriceResult, err error) {
+	if r.merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog Repricer Reprice: ", err.Error())
+		return
+	}
+	if r.catalogID == "" {
+		err = ErrCatalogNotSpecified
+		glg.Error("[SDK] Catalog Repricer Reprice: ", err.Error())
+		return
+	}
+	workers := r.Workers
+	if workers <= 0 {
+		workers = DefaultSnapshotWorkers
+	}
+	ps, cs, err := catalogContent(r.service, r.merchantID, r.catalogID, workers)
+	if err != nil {
+		glg.Error("[SDK] Catalog Repricer Reprice catalogContent: ", err.Error())
+		return
+	}
+	productIDs := make(map[string]string)
+	for _, p := range ps {
+		if p.ExternalCode != "" {
+			productIDs[p.ExternalCode] = p.ID
+		}
+	}
+	links := make(map[string][]itemLink)
+	for _, c := range cs {
+		for _, item := range c.Items {
+			links[item.ProductID] = append(links[item.ProductID], itemLink{categoryID: c.ID, item: item})
+			if _, ok := productIDs[item.ExternalCode]; !ok && (item.ExternalCode != "") {
+				productIDs[item.ExternalCode] = item.ProductID
+			}
+		}
+	}
+	var pending []pendingPrice
+	for _, u := range updates {
+		if err := u.verify(); err != nil {
+			results = append(results, PriceResult{PriceUpdate: u, Err: err})
+			continue
+		}
+		productID := u.ProductID
+		if productID == "" {
+			productID = productIDs[u.ExternalCode]
+		}
+		if len(links[productID]) == 0 {
+			results = append(results, PriceResult{PriceUpdate: u, Err: ErrItemNotFound})
+			continue
+		}
+		for _, link := range links[productID] {
+			res := PriceResult{PriceUpdate: u, CategoryID: link.categoryID, Previous: link.item.Price}
+			res.ProductID = productID
+			if res.Unchanged = (link.item.Price == u.price()); !res.Unchanged {
+				pending = append(pending, pendingPrice{index: len(results), item: link.item})
+			}
+			results = append(results, res)
+		}
+	}
+	r.update(results, pending, workers)
+	glg.Infof("[SDK] Catalog Repricer Reprice %d items updated, merchant '%s'", len(pending), r.merchantID)
+	return
+}
+
+// update edits the pending results, waiting Interval between requests
+func (r *Repricer) update(results []PriceResult, pending []pendingPrice, workers int) {
+	var tick <-chan time.Time
+	if r.Interval > 0 {
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	var mu sync.Mutex
+	done := 0
+	tasks := make([]func() error, len(pending))
+	for i, p := range pending {
+		res, item := &results[p.index], p.item
+		tasks[i] = func() error {
+			if tick != nil {
+				<-tick
+			}
+			ci := CategoryItem{
+				Status:              item.Status,
+				ExternalCode:        item.ExternalCode,
+				DietaryRestrictions: item.DietaryRestrictions,
+				Sequence:            item.Sequence,
+				Price:               res.price(),
+				Shifts:              item.Shifts,
+			}
+			_, res.Err = r.service.EditItem(r.merchantID, res.CategoryID, res.ProductID, ci)
+			if res.Err != nil {
+				glg.Errorf("[SDK] Catalog Repricer Reprice product '%s' category '%s': %s",
+					res.ProductID, res.CategoryID, res.Err.Error())
+			}
+			mu.Lock()
+			done++
+			if r.Progress != nil {
+				r.Progress(done, len(pending))
+			}
+			mu.Unlock()
+			return nil
+		}
+	}
+	runBounded(workers, tasks)
+}
+
+// FailedPrices results that could not be applied
+func FailedPrices(results []PriceResult) (failed []PriceResult) {
+	for _, res := range results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *liveMenu) EditItem(merchantID, categoryID, productID string, ci CategoryItem) (ProductLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if productID == m.failCreate {
+		return ProductLink{}, errors.New("edit failed")
+	}
+	c := m.categories[categoryID]
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items[i].Price, c.Items[i].Status, c.Items[i].Shifts = ci.Price, ci.Status, ci.Shifts
+		}
+	}
+	m.write("edit item %s %s", categoryID, productID)
+	return ProductLink{ID: productID, Price: ci.Price}, nil
+}
+
+func repriceMenu() *liveMenu {
+	m := newLiveMenu()
+	item := func(productID string, value float64) Item {
+		return Item{ProductID: productID, Status: "AVAILABLE", Price: Price{Value: value}, Shifts: shifts}
+	}
+	m.categories["cat_lanches"] = CategoryResponse{ID: "cat_lanches", ExternalCode: "c-lanches",
+		Items: []Item{item("id_old", 10), item("id_burger", 20)}}
+	m.categories["cat_combos"] = CategoryResponse{ID: "cat_combos",
+		Items: []Item{item("id_burger", 20), item("id_manual", 5)}}
+	m.categories["cat_manual"] = CategoryResponse{ID: "cat_manual",
+		Items: []Item{{ProductID: "id_legacy", ExternalCode: "i-legacy", Status: "AVAILABLE", Price: Price{Value: 7}, Shifts: shifts}}}
+	delete(m.categories, "cat_old")
+	return m
+}
+
+func TestReprice(t *testing.T) {
+	m := repriceMenu()
+	r := NewRepricer(m, "merchant_id", "catalog_id")
+	r.Interval = 0
+	progress := []int{}
+	r.Progress = func(done, total int) {
+		assert.Equal(t, 4, total)
+		progress = append(progress, done)
+	}
+	results, err := r.Reprice([]PriceUpdate{
+		{ExternalCode: "p-burger", Value: 18, OriginalValue: 22},
+		{ProductID: "id_manual", Value: 6},
+		{ExternalCode: "p-old", Value: 10},
+		{ExternalCode: "i-legacy", Value: 8},
+		{ExternalCode: "p-unknown", Value: 8},
+		{ExternalCode: "p-old", Value: 10, OriginalValue: 9},
+		{Value: 10},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, progress)
+	assert.Equal(t, 8, len(results))
+	assert.Equal(t, "id_burger", results[0].ProductID)
+	assert.Equal(t, Price{Value: 20}, results[0].Previous)
+	assert.ElementsMatch(t, []string{"cat_combos", "cat_lanches"}, []string{results[0].CategoryID, results[1].CategoryID})
+	assert.Equal(t, "cat_combos", results[2].CategoryID)
+	assert.True(t, results[3].Unchanged)
+	assert.Equal(t, "id_legacy", results[4].ProductID)
+	assert.Equal(t, ErrItemNotFound, results[5].Err)
+	assert.True(t, errors.Is(results[6].Err, ErrInvalidPromotionPrice))
+	assert.Equal(t, ErrNoPriceTarget, results[7].Err)
+	assert.Equal(t, 3, len(FailedPrices(results)))
+
+	promotion := Price{Value: 18, OriginalValue: 22}
+	for _, id := range []string{"cat_lanches", "cat_combos"} {
+		for _, item := range m.categories[id].Items {
+			if item.ProductID == "id_burger" {
+				assert.Equal(t, promotion, item.Price)
+				assert.Equal(t, shifts, item.Shifts)
+			}
+		}
+	}
+	assert.Equal(t, Price{Value: 8}, m.categories["cat_manual"].Items[0].Price)
+	assert.Equal(t, 4, len(m.writes))
+}
+
+func TestReprice_Errors(t *testing.T) {
+	m := repriceMenu()
+	m.failCreate = "id_manual"
+	r := NewRepricer(m, "merchant_id", "catalog_id")
+	r.Interval = 20 * time.Millisecond
+	r.Workers = 3
+	start := time.Now()
+	results, err := r.Reprice([]PriceUpdate{
+		{ProductID: "id_manual", Value: 6},
+		{ExternalCode: "p-burger", Value: 25},
+	})
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 60*time.Millisecond)
+	assert.EqualError(t, results[0].Err, "edit failed")
+	assert.Nil(t, results[1].Err)
+	assert.Equal(t, 1, len(FailedPrices(results)))
+
+	_, err = NewRepricer(m, "", "catalog_id").Reprice(nil)
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+	_, err = NewRepricer(m, "merchant_id", "").Reprice(nil)
+	assert.Equal(t, ErrCatalogNotSpecified, err)
+	_, err = NewRepricer(&menuService{failOn: "categories"}, "merchant_id", "catalog_id").Reprice(nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,203 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/kpango/glg"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSnapshotWorkers concurrent requests used when none is given
+const DefaultSnapshotWorkers = 4
+
+type (
+	// MenuSnapshot every catalog, category, item, product and pizza of a merchant
+	MenuSnapshot struct {
+		MerchantID string            `json:"merchantId"`
+		Catalogs   []CatalogSnapshot `json:"catalogs"`
+		Products   Products          `json:"products"`
+		Pizzas     Pizzas            `json:"pizzas"`
+	}
+
+	// CatalogSnapshot catalog with its categories and their items
+	CatalogSnapshot struct {
+		Catalog
+		Categories Categories `json:"categories"`
+	}
+)
+
+// Snapshot walks the whole menu of a merchant with at most
+// workers concurrent requests and returns it as a single tree
+//
+// every list is sorted (catalogs, products and pizzas by id, categories,
+// items, option groups and pizza parts by sequence) so two snapshots
+// of the same menu are identical
+func Snapshot(service Service, merchantID string, workers int) (ms MenuSnapshot, err error) {
+	if merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog Snapshot: ", err.Error())
+		return
+	}
+	if workers <= 0 {
+		workers = DefaultSnapshotWorkers
+	}
+	catalogs, err := service.ListAllV2(merchantID)
+	if err != nil {
+		glg.Error("[SDK] Catalog Snapshot ListAllV2: ", err.Error())
+		return
+	}
+	ms = MenuSnapshot{MerchantID: merchantID, Catalogs: make([]CatalogSnapshot, len(catalogs))}
+	tasks := []func() error{
+		func() (err error) {
+			ms.Products, err = service.ListProducts(merchantID)
+			return
+		},
+		func() (err error) {
+			ms.Pizzas, err = service.ListPizzas(merchantID)
+			return
+		},
+	}
+	for i := range catalogs {
+		cs := &ms.Catalogs[i]
+		cs.Catalog = catalogs[i]
+		tasks = append(tasks, func() (err error) {
+			cs.Categories, err = service.ListCategoriesInCatalog(merchantID, cs.ID)
+			return
+		})
+	}
+	if err = runBounded(workers, tasks); err != nil {
+		glg.Error("[SDK] Catalog Snapshot: ", err.Error(), " merchant: ", merchantID)
+		return MenuSnapshot{}, err
+	}
+	tasks = nil
+	for i := range ms.Catalogs {
+		cs := &ms.Catalogs[i]
+		for j := range cs.Categories {
+			category := &cs.Categories[j]
+			tasks = append(tasks, func() (err error) {
+				*category, err = service.GetCategoryInCatalog(merchantID, cs.ID, category.ID)
+				return
+			})
+		}
+	}
+	if err = runBounded(workers, tasks); err != nil {
+		glg.Error("[SDK] Catalog Snapshot: ", err.Error(), " merchant: ", merchantID)
+		return MenuSnapshot{}, err
+	}
+	ms.sort()
+	glg.Infof("[SDK] Catalog Snapshot success, merchant '%s'", merchantID)
+	return
+}
+
+// JSON indented snapshot
+func (ms MenuSnapshot) JSON() ([]byte, error) {
+	return json.MarshalIndent(ms, "", "  ")
+}
+
+// YAML snapshot, keys are named and ordered as in JSON
+func (ms MenuSnapshot) YAML() (out []byte, err error) {
+	data, err := json.Marshal(ms)
+	if err != nil {
+		return
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(data, &node); err != nil {
+		return
+	}
+	blockStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&node); err != nil {
+		return
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// blockStyle drops the JSON flow and quoting styles so the output reads
+// as plain YAML, the encoder still quotes strings that need it
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func (ms *MenuSnapshot) sort() {
+	sort.SliceStable(ms.Catalogs, func(i, j int) bool { return ms.Catalogs[i].ID < ms.Catalogs[j].ID })
+	for _, cs := range ms.Catalogs {
+		sort.SliceStable(cs.Categories, func(i, j int) bool {
+			return bySequence(cs.Categories[i].Sequence, cs.Categories[j].Sequence,
+				cs.Categories[i].ID, cs.Categories[j].ID)
+		})
+		for _, category := range cs.Categories {
+			sortItems(category.Items)
+			category.Pizza.sort()
+		}
+	}
+	sort.SliceStable(ms.Products, func(i, j int) bool { return ms.Products[i].ID < ms.Products[j].ID })
+	sort.SliceStable(ms.Pizzas, func(i, j int) bool { return ms.Pizzas[i].ID < ms.Pizzas[j].ID })
+	for i := range ms.Pizzas {
+		ms.Pizzas[i].sort()
+	}
+}
+
+func sortItems(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return bySequence(items[i].Sequence, items[j].Sequence, items[i].ID, items[j].ID)
+	})
+	for _, item := range items {
+		groups := item.OptionGroups
+		sort.SliceStable(groups, func(i, j int) bool {
+			return bySequence(groups[i].Sequence, groups[j].Sequence, groups[i].ID, groups[j].ID)
+		})
+		for _, group := range groups {
+			options := group.Options
+			sort.SliceStable(options, func(i, j int) bool {
+				return bySequence(options[i].Sequence, options[j].Sequence, options[i].ID, options[j].ID)
+			})
+		}
+	}
+}
+
+func (p *Pizza) sort() {
+	for _, parts := range [][]CategoryItem{p.Sizes, p.Crusts, p.Edges, p.Toppings} {
+		sort.SliceStable(parts, func(i, j int) bool {
+			return bySequence(parts[i].Sequence, parts[j].Sequence, parts[i].ID, parts[j].ID)
+		})
+	}
+}
+
+func bySequence(seqA, seqB int, idA, idB string) bool {
+	if seqA != seqB {
+		return seqA < seqB
+	}
+	return idA < idB
+}
+
+// runBounded runs the tasks with at most workers at a time and
+// returns the error of the first failing task in the given order
+func runBounded(workers int, tasks []func() error) error {
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, task func() error) {
+			defer wg.Done()
+			errs[i] = task()
+			<-sem
+		}(i, task)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
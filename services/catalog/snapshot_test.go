@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type menuService struct {
+	Service
+	mu       sync.Mutex
+	running  int
+	peak     int
+	failOn   string
+	requests int
+}
+
+func (s *menuService) call(name string) error {
+	s.mu.Lock()
+	s.running++
+	s.requests++
+	if s.running > s.peak {
+		s.peak = s.running
+	}
+	s.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+	if name == s.failOn {
+		return errors.New("failed " + name)
+	}
+	return nil
+}
+
+func (s *menuService) ListAllV2(merchantID string) (Catalogs, error) {
+	return Catalogs{{ID: "catalog_b"}, {ID: "catalog_a", Context: []string{"WHITELABEL"}}}, s.call("catalogs")
+}
+
+func (s *menuService) ListCategoriesInCatalog(merchantUUID, catalogID string) (Categories, error) {
+	return Categories{{ID: catalogID + "_2", Sequence: 2}, {ID: catalogID + "_1", Sequence: 1}}, s.call("categories")
+}
+
+func (s *menuService) GetCategoryInCatalog(merchantUUID, catalogID, categoryID string) (CategoryResponse, error) {
+	cr := CategoryResponse{ID: categoryID, Name: "category " + categoryID, Sequence: 1}
+	if strings.HasSuffix(categoryID, "_2") {
+		cr.Sequence = 2
+	}
+	cr.Items = []Item{{ID: "item_2", Sequence: 1}, {ID: "item_1", Sequence: 1}}
+	return cr, s.call(categoryID)
+}
+
+func (s *menuService) ListProducts(merchantUUID string) (Products, error) {
+	return Products{{ID: "product_2", Name: "true"}, {ID: "product_1", Name: "X-Burger"}}, s.call("products")
+}
+
+func (s *menuService) ListPizzas(merchantUUID string) (Pizzas, error) {
+	return Pizzas{{ID: "pizza_1", Sizes: []CategoryItem{{ID: "big", Sequence: 2}, {ID: "small", Sequence: 1}}}},
+		s.call("pizzas")
+}
+
+func TestSnapshot(t *testing.T) {
+	service := &menuService{}
+	ms, err := Snapshot(service, "merchant_id", 2)
+	assert.Nil(t, err)
+	assert.True(t, service.peak <= 2)
+	// catalogs, products, pizzas, 2 category lists and 4 categories
+	assert.Equal(t, 9, service.requests)
+
+	assert.Equal(t, "merchant_id", ms.MerchantID)
+	assert.Equal(t, "catalog_a", ms.Catalogs[0].ID)
+	assert.Equal(t, "catalog_a_1", ms.Catalogs[0].Categories[0].ID)
+	assert.Equal(t, "category catalog_a_2", ms.Catalogs[0].Categories[1].Name)
+	assert.Equal(t, "item_1", ms.Catalogs[1].Categories[0].Items[0].ID)
+	assert.Equal(t, "product_1", ms.Products[0].ID)
+	assert.Equal(t, "small", ms.Pizzas[0].Sizes[0].ID)
+
+	again, err := Snapshot(&menuService{}, "merchant_id", 0)
+	assert.Nil(t, err)
+	first, err := ms.JSON()
+	assert.Nil(t, err)
+	second, err := again.JSON()
+	assert.Nil(t, err)
+	assert.Equal(t, string(first), string(second))
+
+	decoded := MenuSnapshot{}
+	assert.Nil(t, json.Unmarshal(first, &decoded))
+	assert.Equal(t, ms, decoded)
+}
+
+func TestSnapshot_YAML(t *testing.T) {
+	ms, err := Snapshot(&menuService{}, "merchant_id", 1)
+	assert.Nil(t, err)
+	out, err := ms.YAML()
+	assert.Nil(t, err)
+	yaml := string(out)
+	assert.True(t, strings.HasPrefix(yaml, "merchantId: merchant_id\ncatalogs:\n  - catalogId: catalog_a\n"))
+	assert.Contains(t, yaml, "name: X-Burger")
+	// strings that look like other types stay strings
+	assert.Contains(t, yaml, `name: "true"`)
+}
+
+func TestSnapshot_Errors(t *testing.T) {
+	_, err := Snapshot(&menuService{}, "", 1)
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+	_, err = Snapshot(&menuService{failOn: "catalogs"}, "merchant_id", 1)
+	assert.EqualError(t, err, "failed catalogs")
+	_, err = Snapshot(&menuService{failOn: "pizzas"}, "merchant_id", 3)
+	assert.EqualError(t, err, "failed pizzas")
+	_, err = Snapshot(&menuService{failOn: "catalog_b_2"}, "merchant_id", 3)
+	assert.EqualError(t, err, "failed catalog_b_2")
+}
@@ -0,0 +1,303 @@
+package catalog
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/kpango/glg"
+)
+
+type (
+	// SyncState local catalog cache and the time it is up to date with,
+	// it can be stored as JSON between runs
+	SyncState struct {
+		Checkpoint time.Time    `json:"checkpoint"`
+		Menu       MenuSnapshot `json:"menu"`
+	}
+
+	// SyncResult what a Sync did
+	SyncResult struct {
+		// Full the whole menu was downloaded again
+		Full bool
+		// Entries changelog entries applied
+		Entries int
+		// Refreshed categories downloaded again
+		Refreshed int
+	}
+
+	// CatalogSync keeps a SyncState up to date with the catalog changelog
+	CatalogSync struct {
+		service    Service
+		merchantID string
+		state      SyncState
+		// Workers concurrent requests used to download changed entities
+		Workers int
+	}
+
+	categoryKey struct {
+		catalogID  string
+		categoryID string
+	}
+)
+
+// NewCatalogSync starts from a stored state, an empty state
+// downloads the whole menu on the first Sync
+func NewCatalogSync(service Service, merchantID string, state SyncState) *CatalogSync {
+	return &CatalogSync{
+		service:    service,
+		merchantID: merchantID,
+		state:      state,
+		Workers:    DefaultSnapshotWorkers,
+	}
+}
+
+// State current cache and checkpoint
+func (s *CatalogSync) State() SyncState {
+	return s.state
+}
+
+// Sync applies the changelog entries from the checkpoint until the given time
+//
+// deleted entities are dropped from the cache, changed categories and items
+// download their category again and products and pizzas are taken from the
+// entry data, or listed again when it is missing. Entries that can not be
+// placed in the cache, as a category of an unknown catalog, download the
+// whole menu. The cache and the checkpoint only change when every download
+// succeeds, so a failed Sync can be run again
+func (s *CatalogSync) Sync(until time.Time) (res SyncResult, err error) {
+	if s.merchantID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Catalog Sync: ", err.Error())
+		return
+	}
+	if s.state.Checkpoint.IsZero() || (s.state.Menu.MerchantID != s.merchantID) {
+		return s.resync(until)
+	}
+	if !s.state.Checkpoint.Before(until) {
+		return
+	}
+	entries, err := s.service.ListChangelogs(s.merchantID, s.state.Checkpoint, until)
+	if err != nil {
+		glg.Error("[SDK] Catalog Sync ListChangelogs: ", err.Error())
+		return
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CreatedAt.Before(entries[j].CreatedAt) })
+	// changes go to a copy, the cache and the State already returned
+	// stay as they were until every download succeeds
+	menu := s.state.Menu.clone()
+	refresh := make(map[categoryKey]bool)
+	var products, pizzas bool
+	for _, e := range entries {
+		switch e.EntityType {
+		case EntityProduct:
+			var p Product
+			if e.Action == ChangeDeleted {
+				menu.removeProduct(e.EntityID)
+			} else if (len(e.Data) > 0) && (json.Unmarshal(e.Data, &p) == nil) && (p.ID == e.EntityID) {
+				menu.upsertProduct(p)
+			} else {
+				products = true
+			}
+		case EntityPizza:
+			pizzas = true
+		case EntityCategory, EntityItem:
+			categoryID := e.EntityID
+			if e.EntityType == EntityItem {
+				categoryID = e.CategoryID
+			}
+			key, ok := menu.locate(e.CatalogID, categoryID)
+			if !ok {
+				glg.Warnf("[SDK] Catalog Sync %s '%s' is not in the cache, downloading the whole menu",
+					e.EntityType, e.EntityID)
+				return s.resync(until)
+			}
+			if (e.EntityType == EntityCategory) && (e.Action == ChangeDeleted) {
+				menu.removeCategory(key)
+				delete(refresh, key)
+				continue
+			}
+			refresh[key] = true
+		case EntityOptionGroup, EntityOption:
+			group := e.EntityID
+			if e.EntityType == EntityOption {
+				group = e.OptionGroupID
+			}
+			for _, key := range menu.categoriesWithGroup(group) {
+				refresh[key] = true
+			}
+		}
+	}
+	if err = s.download(&menu, refresh, products, pizzas); err != nil {
+		glg.Error("[SDK] Catalog Sync: ", err.Error(), " merchant: ", s.merchantID)
+		return
+	}
+	menu.sort()
+	s.state = SyncState{Checkpoint: until, Menu: menu}
+	glg.Infof("[SDK] Catalog Sync applied %d entries, merchant '%s'", len(entries), s.merchantID)
+	return SyncResult{Entries: len(entries), Refreshed: len(refresh)}, nil
+}
+
+func (s *CatalogSync) resync(until time.Time) (res SyncResult, err error) {
+	menu, err := Snapshot(s.service, s.merchantID, s.Workers)
+	if err != nil {
+		return
+	}
+	s.state = SyncState{Checkpoint: until, Menu: menu}
+	return SyncResult{Full: true}, nil
+}
+
+// download fetches what changed and applies it to the menu once every request succeeded
+func (s *CatalogSync) download(menu *MenuSnapshot, refresh map[categoryKey]bool, products, pizzas bool) (err error) {
+	keys := make([]categoryKey, 0, len(refresh))
+	for key := range refresh {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].catalogID != keys[j].catalogID {
+			return keys[i].catalogID < keys[j].catalogID
+		}
+		return keys[i].categoryID < keys[j].categoryID
+	})
+	categories := make([]CategoryResponse, len(keys))
+	var ps Products
+	var pz Pizzas
+	var tasks []func() error
+	for i := range keys {
+		i := i
+		tasks = append(tasks, func() (err error) {
+			categories[i], err = s.service.GetCategoryInCatalog(s.merchantID, keys[i].catalogID, keys[i].categoryID)
+			return
+		})
+	}
+	if products {
+		tasks = append(tasks, func() (err error) {
+			ps, err = s.service.ListProducts(s.merchantID)
+			return
+		})
+	}
+	if pizzas {
+		tasks = append(tasks, func() (err error) {
+			pz, err = s.service.ListPizzas(s.merchantID)
+			return
+		})
+	}
+	workers := s.Workers
+	if workers <= 0 {
+		workers = DefaultSnapshotWorkers
+	}
+	if err = runBounded(workers, tasks); err != nil {
+		return
+	}
+	for i, key := range keys {
+		menu.upsertCategory(key.catalogID, categories[i])
+	}
+	if products {
+		menu.Products = ps
+	}
+	if pizzas {
+		menu.Pizzas = pz
+	}
+	return
+}
+
+// clone copies the slices Sync changes in place
+func (ms MenuSnapshot) clone() MenuSnapshot {
+	c := ms
+	c.Products = append(Products(nil), ms.Products...)
+	c.Pizzas = append(Pizzas(nil), ms.Pizzas...)
+	c.Catalogs = make([]CatalogSnapshot, len(ms.Catalogs))
+	for i, cs := range ms.Catalogs {
+		cs.Categories = append(Categories(nil), cs.Categories...)
+		c.Catalogs[i] = cs
+	}
+	return c
+}
+
+// locate the catalog of a category, new categories need the catalog id
+func (ms *MenuSnapshot) locate(catalogID, categoryID string) (key categoryKey, ok bool) {
+	for _, cs := range ms.Catalogs {
+		if (catalogID != "") && (cs.ID == catalogID) {
+			return categoryKey{catalogID, categoryID}, true
+		}
+		for _, c := range cs.Categories {
+			if (catalogID == "") && (c.ID == categoryID) {
+				return categoryKey{cs.ID, categoryID}, true
+			}
+		}
+	}
+	return
+}
+
+func (ms *MenuSnapshot) categoriesWithGroup(groupID string) (keys []categoryKey) {
+	for _, cs := range ms.Catalogs {
+		for _, c := range cs.Categories {
+			if c.hasOptionGroup(groupID) {
+				keys = append(keys, categoryKey{cs.ID, c.ID})
+			}
+		}
+	}
+	return
+}
+
+func (c CategoryResponse) hasOptionGroup(groupID string) bool {
+	for _, item := range c.Items {
+		for _, group := range item.OptionGroups {
+			if group.ID == groupID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (ms *MenuSnapshot) upsertCategory(catalogID string, category CategoryResponse) {
+	for i := range ms.Catalogs {
+		cs := &ms.Catalogs[i]
+		if cs.ID != catalogID {
+			continue
+		}
+		for j := range cs.Categories {
+			if cs.Categories[j].ID == category.ID {
+				cs.Categories[j] = category
+				return
+			}
+		}
+		cs.Categories = append(cs.Categories, category)
+		return
+	}
+}
+
+func (ms *MenuSnapshot) removeCategory(key categoryKey) {
+	for i := range ms.Catalogs {
+		cs := &ms.Catalogs[i]
+		if cs.ID != key.catalogID {
+			continue
+		}
+		for j := range cs.Categories {
+			if cs.Categories[j].ID == key.categoryID {
+				cs.Categories = append(cs.Categories[:j], cs.Categories[j+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (ms *MenuSnapshot) upsertProduct(p Product) {
+	for i := range ms.Products {
+		if ms.Products[i].ID == p.ID {
+			ms.Products[i] = p
+			return
+		}
+	}
+	ms.Products = append(ms.Products, p)
+}
+
+func (ms *MenuSnapshot) removeProduct(productID string) {
+	for i := range ms.Products {
+		if ms.Products[i].ID == productID {
+			ms.Products = append(ms.Products[:i], ms.Products[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type changingMenu struct {
+	*menuService
+	entries    Changelogs
+	entriesErr error
+	version    string
+	fetched    []string
+}
+
+func (m *changingMenu) ListChangelogs(merchantID string, from, to time.Time) (Changelogs, error) {
+	return m.entries, m.entriesErr
+}
+
+func (m *changingMenu) GetCategoryInCatalog(merchantUUID, catalogID, categoryID string) (CategoryResponse, error) {
+	cr, err := m.menuService.GetCategoryInCatalog(merchantUUID, catalogID, categoryID)
+	cr.Name += m.version
+	if categoryID == "catalog_b_1" {
+		cr.Items[0].OptionGroups = []OptionGroup{{ID: "group_1"}}
+	}
+	m.mu.Lock()
+	m.fetched = append(m.fetched, categoryID)
+	m.mu.Unlock()
+	return cr, err
+}
+
+func TestCatalogSync(t *testing.T) {
+	menu := &changingMenu{menuService: &menuService{}}
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	sync := NewCatalogSync(menu, "merchant_id", SyncState{})
+	res, err := sync.Sync(start)
+	assert.Nil(t, err)
+	assert.True(t, res.Full)
+	assert.Equal(t, start, sync.State().Checkpoint)
+	assert.Equal(t, 2, len(sync.State().Menu.Catalogs))
+
+	menu.version = " v2"
+	menu.fetched = nil
+	requests := menu.requests
+	menu.entries = Changelogs{
+		{EntityType: EntityOptionGroup, EntityID: "group_1", Action: ChangeUpdated, CreatedAt: start.Add(4 * time.Minute)},
+		{EntityType: EntityItem, EntityID: "item_1", CategoryID: "catalog_a_1", Action: ChangeUpdated, CreatedAt: start.Add(time.Minute)},
+		{EntityType: EntityProduct, EntityID: "product_3", Action: ChangeCreated, CreatedAt: start.Add(2 * time.Minute),
+			Data: json.RawMessage(`{"id": "product_3", "name": "Suco"}`)},
+		{EntityType: EntityProduct, EntityID: "product_2", Action: ChangeDeleted, CreatedAt: start.Add(3 * time.Minute)},
+		{EntityType: EntityCategory, EntityID: "catalog_b_2", CatalogID: "catalog_b", Action: ChangeUpdated, CreatedAt: start.Add(time.Minute)},
+		{EntityType: EntityCategory, EntityID: "catalog_b_2", CatalogID: "catalog_b", Action: ChangeDeleted, CreatedAt: start.Add(5 * time.Minute)},
+	}
+	res, err = sync.Sync(start.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, SyncResult{Entries: 6, Refreshed: 2}, res)
+	assert.ElementsMatch(t, []string{"catalog_a_1", "catalog_b_1"}, menu.fetched)
+	assert.Equal(t, requests+2, menu.requests)
+
+	state := sync.State()
+	assert.Equal(t, start.Add(time.Hour), state.Checkpoint)
+	assert.Equal(t, "category catalog_a_1 v2", state.Menu.Catalogs[0].Categories[0].Name)
+	assert.Equal(t, "category catalog_a_2", state.Menu.Catalogs[0].Categories[1].Name)
+	assert.Equal(t, 1, len(state.Menu.Catalogs[1].Categories))
+	assert.Equal(t, "category catalog_b_1 v2", state.Menu.Catalogs[1].Categories[0].Name)
+	assert.Equal(t, Products{{ID: "product_1", Name: "X-Burger"}, {ID: "product_3", Name: "Suco"}}, state.Menu.Products)
+
+	// the state survives being stored and a product without data is listed again
+	stored, err := json.Marshal(state)
+	assert.Nil(t, err)
+	loaded := SyncState{}
+	assert.Nil(t, json.Unmarshal(stored, &loaded))
+	sync = NewCatalogSync(menu, "merchant_id", loaded)
+	menu.entries = Changelogs{{EntityType: EntityProduct, EntityID: "product_2", Action: ChangeCreated}}
+	res, err = sync.Sync(start.Add(2 * time.Hour))
+	assert.Nil(t, err)
+	assert.False(t, res.Full)
+	assert.Equal(t, 2, len(sync.State().Menu.Products))
+	assert.Equal(t, "product_2", sync.State().Menu.Products[1].ID)
+}
+
+func TestCatalogSync_Fallbacks(t *testing.T) {
+	menu := &changingMenu{menuService: &menuService{}}
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	sync := NewCatalogSync(menu, "merchant_id", SyncState{})
+	_, err := sync.Sync(start)
+	assert.Nil(t, err)
+
+	res, err := sync.Sync(start)
+	assert.Nil(t, err)
+	assert.Equal(t, SyncResult{}, res)
+
+	menu.entriesErr = errors.New("changelog down")
+	_, err = sync.Sync(start.Add(time.Hour))
+	assert.EqualError(t, err, "changelog down")
+	assert.Equal(t, start, sync.State().Checkpoint)
+
+	// entries applied before a failed download are not kept
+	before := sync.State()
+	products := append(Products(nil), before.Menu.Products...)
+	categories := len(before.Menu.Catalogs[1].Categories)
+	menu.entriesErr = nil
+	menu.failOn = "catalog_a_1"
+	menu.entries = Changelogs{
+		{EntityType: EntityProduct, EntityID: "product_1", Action: ChangeDeleted},
+		{EntityType: EntityProduct, EntityID: "product_9", Action: ChangeCreated, Data: json.RawMessage(`{"id": "product_9"}`)},
+		{EntityType: EntityCategory, EntityID: "catalog_b_2", CatalogID: "catalog_b", Action: ChangeDeleted},
+		{EntityType: EntityItem, CategoryID: "catalog_a_1", Action: ChangeUpdated},
+	}
+	_, err = sync.Sync(start.Add(time.Hour))
+	assert.EqualError(t, err, "failed catalog_a_1")
+	assert.Equal(t, start, sync.State().Checkpoint)
+	assert.Equal(t, products, sync.State().Menu.Products)
+	assert.Equal(t, products, before.Menu.Products)
+	assert.Equal(t, categories, len(sync.State().Menu.Catalogs[1].Categories))
+	assert.Equal(t, "catalog_b_2", before.Menu.Catalogs[1].Categories[1].ID)
+
+	menu.failOn = ""
+	menu.entries = Changelogs{{EntityType: EntityCategory, EntityID: "new", CatalogID: "catalog_c", Action: ChangeCreated}}
+	res, err = sync.Sync(start.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, res.Full)
+	assert.Equal(t, start.Add(time.Hour), sync.State().Checkpoint)
+
+	_, err = NewCatalogSync(menu, "", SyncState{}).Sync(start)
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+}
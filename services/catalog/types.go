@@ -34,6 +34,9 @@ type (
 		Restrictions []string `json:"restrictions"`
 	}
 
+	// Categories group of CategoryResponse
+	Categories []CategoryResponse
+
 	// CategoryResponse from API when creating
 	CategoryResponse struct {
 		ID           string `json:"id"`
@@ -48,43 +51,53 @@ type (
 
 	// Item product description
 	Item struct {
-		ID                  string   `json:"id"`
-		Name                string   `json:"name"`
-		Description         string   `json:"description"`
-		ExternalCode        string   `json:"externalCode"`
-		Status              string   `json:"status"`
-		ProductID           string   `json:"productId"`
-		Sequence            int      `json:"sequence"`
-		MagePath            string   `json:"magePath"`
-		Price               Price    `json:"price"`
-		Shifts              []Shift  `json:"shifts"`
-		Serving             string   `json:"serving"`
-		DietaryRestrictions []string `json:"dietaryRestrictions"`
-		Ean                 string   `json:"ean"`
-		OptionGroups        []struct {
-			ID           string `json:"id"`
-			Name         string `json:"name"`
-			ExternalCode string `json:"externalCode"`
-			Status       string `json:"status"`
-			Sequence     int    `json:"sequence"`
-			Min          int    `json:"min"`
-			Max          int    `json:"max"`
-			Options      struct {
-				ID           string `json:"id"`
-				Status       string `json:"status"`
-				Sequence     int    `json:"sequence"`
-				ProductID    string `json:"productId"`
-				Name         string `json:"name"`
-				Description  string `json:"description"`
-				ExternalCode string `json:"externalCode"`
-				ImagePath    string `json:"imagePath"`
-				Price        Price  `json:"price"`
-			} `json:"options"`
-		} `json:"optionGroups"`
+		ID                  string        `json:"id"`
+		Name                string        `json:"name"`
+		Description         string        `json:"description"`
+		ExternalCode        string        `json:"externalCode"`
+		Status              string        `json:"status"`
+		ProductID           string        `json:"productId"`
+		Sequence            int           `json:"sequence"`
+		MagePath            string        `json:"magePath"`
+		Price               Price         `json:"price"`
+		Shifts              []Shift       `json:"shifts"`
+		Serving             string        `json:"serving"`
+		DietaryRestrictions []string      `json:"dietaryRestrictions"`
+		Ean                 string        `json:"ean"`
+		OptionGroups        []OptionGroup `json:"optionGroups"`
 		// SellingOption struct {
 		// } `json:"sellingOption"`
 	}
 
+	// OptionGroups group of OptionGroup
+	OptionGroups []OptionGroup
+
+	// OptionGroup complements of a product, customers
+	// pick between Min and Max of its options
+	OptionGroup struct {
+		ID           string   `json:"id"`
+		Name         string   `json:"name"`
+		ExternalCode string   `json:"externalCode"`
+		Status       string   `json:"status"`
+		Sequence     int      `json:"sequence"`
+		Min          int      `json:"min"`
+		Max          int      `json:"max"`
+		Options      []Option `json:"options"`
+	}
+
+	// Option complement of an OptionGroup
+	Option struct {
+		ID           string `json:"id"`
+		Status       string `json:"status"`
+		Sequence     int    `json:"sequence"`
+		ProductID    string `json:"productId"`
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		ExternalCode string `json:"externalCode"`
+		ImagePath    string `json:"imagePath"`
+		Price        Price  `json:"price"`
+	}
+
 	// CategoryItem linked product to a category
 	CategoryItem struct {
 		ID                  string    `json:"id"`
@@ -0,0 +1,208 @@
+package disputes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/adapters"
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/kpango/glg"
+)
+
+const v1Endpoint = "/v1.0/disputes"
+
+type (
+	// Service describes the dispute (handshake) API abstraction
+	Service interface {
+		Get(disputeID string) (Dispute, error)
+		Accept(disputeID string) error
+		Reject(disputeID, reason string) error
+		Propose(disputeID string, proposal Proposal) error
+	}
+
+	disputesService struct {
+		adapter adapters.Http
+		auth    auth.Service
+	}
+)
+
+// New returns a new disputes service
+func New(adapter adapters.Http, authService auth.Service) *disputesService {
+	return &disputesService{adapter, authService}
+}
+
+// FromEvent reads the dispute sent on a HANDSHAKE_DISPUTE event
+func FromEvent(event events.Event) (d Dispute, err error) {
+	if event.Name() != "HANDSHAKE_DISPUTE" {
+		err = fmt.Errorf("event '%s' is not a dispute", event.Code)
+		return
+	}
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(metadata, &d); err != nil {
+		return
+	}
+	if d.OrderID == "" {
+		d.OrderID = event.CorrelationID
+	}
+	if d.ID == "" {
+		err = ErrDisputeNotSpecified
+	}
+	return
+}
+
+// Remaining time to answer the dispute before its timeout action
+func (d Dispute) Remaining(now time.Time) time.Duration {
+	if remaining := d.ExpiresAt.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Expired reports if the dispute can no longer be answered
+func (d Dispute) Expired(now time.Time) bool {
+	return !d.ExpiresAt.IsZero() && !now.Before(d.ExpiresAt)
+}
+
+// Alternative returns an offered alternative by its id
+func (d Dispute) Alternative(alternativeID string) (alternative Alternative, ok bool) {
+	for _, alternative = range d.Alternatives {
+		if alternative.ID == alternativeID {
+			return alternative, true
+		}
+	}
+	return Alternative{}, false
+}
+
+// Verify checks the proposal against the offered alternative limits
+func (d Dispute) Verify(proposal Proposal) (err error) {
+	alternative, ok := d.Alternative(proposal.AlternativeID)
+	if !ok || (alternative.Type != proposal.Type) {
+		return ErrAlternativeNotOffered
+	}
+	switch alternative.Type {
+	case AlternativeRefund:
+		if proposal.Amount == nil || proposal.Amount.Value <= 0 {
+			return ErrInvalidAlternative
+		}
+		if limit := alternative.Metadata.MaxAmount; limit != nil && proposal.Amount.Value > limit.Value {
+			return ErrInvalidAlternative
+		}
+	case AlternativeAdditionalTime:
+		for _, minutes := range alternative.Metadata.AllowedsAdditionalTimeInMinutes {
+			if minutes == proposal.AdditionalTimeInMinutes {
+				return
+			}
+		}
+		return ErrInvalidAlternative
+	}
+	return
+}
+
+// Get retorna os detalhes de uma disputa
+func (s *disputesService) Get(disputeID string) (d Dispute, err error) {
+	if disputeID == "" {
+		err = ErrDisputeNotSpecified
+		glg.Error("[SDK] Disputes Get: ", err.Error())
+		return
+	}
+	if err = s.auth.Validate(); err != nil {
+		glg.Error("[SDK] Disputes Get auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", s.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s", v1Endpoint, disputeID)
+	resp, status, err := s.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Disputes Get adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Disputes Get status code: ", status, " disputeID: ", disputeID)
+		err = fmt.Errorf("Dispute '%s' could not be retrieved", disputeID)
+		glg.Error("[SDK] Disputes Get err: ", err)
+		return
+	}
+	return d, json.Unmarshal(resp, &d)
+}
+
+// Accept aceita o pedido do cliente
+func (s *disputesService) Accept(disputeID string) (err error) {
+	if disputeID == "" {
+		err = ErrDisputeNotSpecified
+		glg.Error("[SDK] Disputes Accept: ", err.Error())
+		return
+	}
+	return s.answer("Accept", disputeID, "accept", nil)
+}
+
+// Reject recusa o pedido do cliente
+func (s *disputesService) Reject(disputeID, reason string) (err error) {
+	if disputeID == "" {
+		err = ErrDisputeNotSpecified
+		glg.Error("[SDK] Disputes Reject: ", err.Error())
+		return
+	}
+	if reason == "" {
+		err = ErrReasonNotSpecified
+		glg.Error("[SDK] Disputes Reject: ", err.Error())
+		return
+	}
+	return s.answer("Reject", disputeID, "reject", rejection{Reason: reason})
+}
+
+// Propose oferece uma das alternativas da disputa ao cliente
+func (s *disputesService) Propose(disputeID string, proposal Proposal) (err error) {
+	if disputeID == "" {
+		err = ErrDisputeNotSpecified
+		glg.Error("[SDK] Disputes Propose: ", err.Error())
+		return
+	}
+	if proposal.AlternativeID == "" {
+		err = ErrAlternativeNotSpecified
+		glg.Error("[SDK] Disputes Propose: ", err.Error())
+		return
+	}
+	return s.answer("Propose", disputeID, "alternatives/"+proposal.AlternativeID, proposal)
+}
+
+func (s *disputesService) answer(method, disputeID, path string, body interface{}) (err error) {
+	if err = s.auth.Validate(); err != nil {
+		glg.Error("[SDK] Disputes ", method, " auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", s.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s/%s", v1Endpoint, disputeID, path)
+	var reader io.Reader
+	if body != nil {
+		headers["Content-Type"] = "application/json"
+		if reader, err = httpadapter.NewJsonReader(body); err != nil {
+			glg.Error("[SDK] Disputes ", method, " NewJsonReader error: ", err.Error())
+			return
+		}
+	}
+	resp, status, err := s.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	if err != nil {
+		glg.Error("[SDK] Disputes ", method, " adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusAccepted {
+		badResp := &apiError{}
+		json.Unmarshal(resp, badResp)
+		glg.Error("[SDK] Disputes ", method, " status code: ", status, " disputeID: ", disputeID, " detail: ", badResp.Details.Code)
+		err = fmt.Errorf("Dispute '%s' could not be answered with '%s', status '%d', code: '%s'",
+			disputeID, path, status, badResp.Details.Code)
+		glg.Error("[SDK] Disputes ", method, " err: ", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,187 @@
+package disputes
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/mocks"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const dispute = `{
+	"disputeId": "dispute_id",
+	"orderId": "order_id",
+	"action": "PARTIAL_CANCELLATION",
+	"message": "Faltou o refrigerante",
+	"handshakeType": "AFTER_DELIVERY",
+	"timeoutAction": "ACCEPT_AUTOMATICALLY",
+	"createdAt": "2021-03-01T20:30:00Z",
+	"expiresAt": "2021-03-01T20:35:00Z",
+	"items": [{"index": 1, "name": "Refrigerante", "quantity": 1, "amount": {"value": 500, "currency": "BRL"}}],
+	"alternatives": [
+		{"id": "alt_refund", "type": "REFUND", "metadata": {"maxAmount": {"value": 500, "currency": "BRL"}}},
+		{"id": "alt_time", "type": "ADDITIONAL_DELIVERY_TIME", "metadata": {"allowedsAdditionalTimeInMinutes": [10, 20]}}
+	]
+}`
+
+func TestGet_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1.0/disputes/dispute_id", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, dispute)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	disputesService := New(adapter, &am)
+	d, err := disputesService.Get("dispute_id")
+	assert.Nil(t, err)
+	assert.Equal(t, ActionPartialCancellation, d.Action)
+	assert.Equal(t, 500, d.Items[0].Amount.Value)
+	assert.Equal(t, 2, len(d.Alternatives))
+}
+
+func TestGet_NoDisputeID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	disputesService := New(adapter, &am)
+	_, err := disputesService.Get("")
+	assert.Equal(t, ErrDisputeNotSpecified, err)
+}
+
+func TestGet_AuthErr(t *testing.T) {
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(errors.New("some err"))
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	disputesService := New(adapter, &am)
+	_, err := disputesService.Get("dispute_id")
+	assert.NotNil(t, err)
+}
+
+func TestAnswers_OK(t *testing.T) {
+	var paths, bodies []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Method, http.MethodPost)
+			body, _ := ioutil.ReadAll(r.Body)
+			paths = append(paths, r.URL.Path)
+			bodies = append(bodies, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	disputesService := New(adapter, &am)
+	assert.Nil(t, disputesService.Accept("dispute_id"))
+	assert.Nil(t, disputesService.Reject("dispute_id", "item entregue"))
+	assert.Nil(t, disputesService.Propose("dispute_id", Proposal{
+		AlternativeID: "alt_refund", Type: AlternativeRefund, Amount: &Amount{Value: 250, Currency: "BRL"},
+	}))
+	assert.Equal(t, []string{
+		"/v1.0/disputes/dispute_id/accept",
+		"/v1.0/disputes/dispute_id/reject",
+		"/v1.0/disputes/dispute_id/alternatives/alt_refund",
+	}, paths)
+	assert.Equal(t, "", bodies[0])
+	assert.JSONEq(t, `{"reason":"item entregue"}`, bodies[1])
+	assert.JSONEq(t, `{"type":"REFUND","amount":{"value":250,"currency":"BRL"}}`, bodies[2])
+}
+
+func TestAnswers_Validation(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	disputesService := New(adapter, &am)
+	assert.Equal(t, ErrDisputeNotSpecified, disputesService.Accept(""))
+	assert.Equal(t, ErrDisputeNotSpecified, disputesService.Reject("", "reason"))
+	assert.Equal(t, ErrReasonNotSpecified, disputesService.Reject("dispute_id", ""))
+	assert.Equal(t, ErrDisputeNotSpecified, disputesService.Propose("", Proposal{AlternativeID: "alt"}))
+	assert.Equal(t, ErrAlternativeNotSpecified, disputesService.Propose("dispute_id", Proposal{}))
+}
+
+func TestAnswers_StatusBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"code":"BadRequest","details":{"code":"DisputeExpired"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	disputesService := New(adapter, &am)
+	err := disputesService.Accept("dispute_id")
+	assert.NotNil(t, err)
+	assert.EqualError(t, err, "Dispute 'dispute_id' could not be answered with 'accept', status '400', code: 'DisputeExpired'")
+}
+
+func TestAnswers_DoReqErr(t *testing.T) {
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	httpmock := &mocks.HttpClientMock{}
+	httpmock.On("Do", mock.Anything).Once().Return(nil, errors.New("some err"))
+	adapter := httpadapter.New(httpmock, "")
+	disputesService := New(adapter, &am)
+	err := disputesService.Accept("dispute_id")
+	assert.NotNil(t, err)
+}
+
+func TestFromEvent(t *testing.T) {
+	metadata := map[string]interface{}{}
+	assert.Nil(t, jsonUnmarshal(dispute, &metadata))
+	delete(metadata, "orderId")
+	d, err := FromEvent(events.Event{Code: "HSD", CorrelationID: "order_id", Metadata: metadata})
+	assert.Nil(t, err)
+	assert.Equal(t, "dispute_id", d.ID)
+	assert.Equal(t, "order_id", d.OrderID)
+	assert.Equal(t, time.Date(2021, 3, 1, 20, 35, 0, 0, time.UTC), d.ExpiresAt)
+
+	_, err = FromEvent(events.Event{Code: "PLACED"})
+	assert.NotNil(t, err)
+	_, err = FromEvent(events.Event{Code: "HANDSHAKE_DISPUTE"})
+	assert.Equal(t, ErrDisputeNotSpecified, err)
+}
+
+func TestDispute_Deadline(t *testing.T) {
+	d := Dispute{ExpiresAt: time.Date(2021, 3, 1, 20, 35, 0, 0, time.UTC)}
+	now := time.Date(2021, 3, 1, 20, 33, 0, 0, time.UTC)
+	assert.Equal(t, 2*time.Minute, d.Remaining(now))
+	assert.False(t, d.Expired(now))
+	assert.True(t, d.Expired(d.ExpiresAt))
+	assert.Equal(t, time.Duration(0), d.Remaining(d.ExpiresAt.Add(time.Minute)))
+	assert.False(t, Dispute{}.Expired(now))
+}
+
+func TestDispute_Verify(t *testing.T) {
+	d := Dispute{}
+	assert.Nil(t, jsonUnmarshal(dispute, &d))
+	assert.Nil(t, d.Verify(Proposal{AlternativeID: "alt_refund", Type: AlternativeRefund, Amount: &Amount{Value: 500}}))
+	assert.Nil(t, d.Verify(Proposal{AlternativeID: "alt_time", Type: AlternativeAdditionalTime, AdditionalTimeInMinutes: 20}))
+	assert.Equal(t, ErrInvalidAlternative,
+		d.Verify(Proposal{AlternativeID: "alt_refund", Type: AlternativeRefund, Amount: &Amount{Value: 501}}))
+	assert.Equal(t, ErrInvalidAlternative, d.Verify(Proposal{AlternativeID: "alt_refund", Type: AlternativeRefund}))
+	assert.Equal(t, ErrInvalidAlternative,
+		d.Verify(Proposal{AlternativeID: "alt_time", Type: AlternativeAdditionalTime, AdditionalTimeInMinutes: 15}))
+	assert.Equal(t, ErrAlternativeNotOffered, d.Verify(Proposal{AlternativeID: "alt_time", Type: AlternativeRefund}))
+	assert.Equal(t, ErrAlternativeNotOffered, d.Verify(Proposal{AlternativeID: "nope"}))
+}
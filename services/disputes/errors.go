@@ -0,0 +1,20 @@
+package disputes
+
+import "errors"
+
+var (
+	// ErrDisputeNotSpecified no dispute id given
+	ErrDisputeNotSpecified = errors.New("Dispute ID was not specified")
+	// ErrReasonNotSpecified no rejection reason given
+	ErrReasonNotSpecified = errors.New("Dispute rejection reason was not specified")
+	// ErrAlternativeNotSpecified no alternative given
+	ErrAlternativeNotSpecified = errors.New("Dispute alternative was not specified")
+	// ErrUnknownDispute the dispute is not open on the tracker
+	ErrUnknownDispute = errors.New("Dispute is not open")
+	// ErrDisputeExpired the dispute deadline has passed
+	ErrDisputeExpired = errors.New("Dispute deadline has passed")
+	// ErrAlternativeNotOffered the alternative is not one of the dispute alternatives
+	ErrAlternativeNotOffered = errors.New("Alternative was not offered on the dispute")
+	// ErrInvalidAlternative the proposal is out of the alternative limits
+	ErrInvalidAlternative = errors.New("Alternative proposal is out of the offered limits")
+)
@@ -0,0 +1,138 @@
+package disputes
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/kpango/glg"
+)
+
+// Tracker keeps the open disputes received through the polled events
+// and refuses answers after their deadline
+type Tracker struct {
+	service  Service
+	callback func(Dispute)
+	now      func() time.Time
+	mu       sync.Mutex
+	open     map[string]Dispute
+}
+
+// NewTracker returns a dispute tracker, callback is called on each new dispute
+func NewTracker(service Service, callback func(Dispute)) *Tracker {
+	return &Tracker{service: service, callback: callback, now: time.Now, open: make(map[string]Dispute)}
+}
+
+// HandleEvent reports if the event opened or settled a dispute
+//
+// HANDSHAKE_DISPUTE opens it, HANDSHAKE_SETTLEMENT closes it
+func (t *Tracker) HandleEvent(event events.Event) bool {
+	switch event.Name() {
+	case "HANDSHAKE_DISPUTE":
+		d, err := FromEvent(event)
+		if err != nil {
+			glg.Error("[SDK] Disputes Tracker FromEvent: ", err.Error())
+			return false
+		}
+		t.mu.Lock()
+		t.open[d.ID] = d
+		t.mu.Unlock()
+		if t.callback != nil {
+			t.callback(d)
+		}
+		return true
+	case "HANDSHAKE_SETTLEMENT":
+		disputeID, _ := event.Metadata["disputeId"].(string)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.open[disputeID]; !ok {
+			return false
+		}
+		delete(t.open, disputeID)
+		return true
+	}
+	return false
+}
+
+// Open returns the disputes waiting for an answer, the closest deadline first
+func (t *Tracker) Open() (open []Dispute) {
+	t.mu.Lock()
+	now := t.now()
+	for id, d := range t.open {
+		if d.Expired(now) {
+			delete(t.open, id)
+			continue
+		}
+		open = append(open, d)
+	}
+	t.mu.Unlock()
+	sort.Slice(open, func(i, j int) bool {
+		if open[i].ExpiresAt.Equal(open[j].ExpiresAt) {
+			return open[i].ID < open[j].ID
+		}
+		return open[i].ExpiresAt.Before(open[j].ExpiresAt)
+	})
+	return
+}
+
+// Accept accepts an open dispute
+func (t *Tracker) Accept(disputeID string) (err error) {
+	if _, err = t.get(disputeID); err != nil {
+		return
+	}
+	if err = t.service.Accept(disputeID); err != nil {
+		return
+	}
+	t.close(disputeID)
+	return
+}
+
+// Reject rejects an open dispute
+func (t *Tracker) Reject(disputeID, reason string) (err error) {
+	if _, err = t.get(disputeID); err != nil {
+		return
+	}
+	if err = t.service.Reject(disputeID, reason); err != nil {
+		return
+	}
+	t.close(disputeID)
+	return
+}
+
+// Propose counter-offers one of the alternatives of an open dispute, the
+// dispute stays open until the customer answer settles it (HANDSHAKE_SETTLEMENT)
+func (t *Tracker) Propose(disputeID string, proposal Proposal) (err error) {
+	d, err := t.get(disputeID)
+	if err != nil {
+		return
+	}
+	if err = d.Verify(proposal); err != nil {
+		glg.Error("[SDK] Disputes Tracker Propose: ", err.Error(), " disputeID: ", disputeID)
+		return
+	}
+	return t.service.Propose(disputeID, proposal)
+}
+
+func (t *Tracker) get(disputeID string) (d Dispute, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.open[disputeID]
+	if !ok {
+		err = ErrUnknownDispute
+		glg.Error("[SDK] Disputes Tracker: ", err.Error(), " disputeID: ", disputeID)
+		return
+	}
+	if d.Expired(t.now()) {
+		delete(t.open, disputeID)
+		err = ErrDisputeExpired
+		glg.Error("[SDK] Disputes Tracker: ", err.Error(), " disputeID: ", disputeID)
+	}
+	return
+}
+
+func (t *Tracker) close(disputeID string) {
+	t.mu.Lock()
+	delete(t.open, disputeID)
+	t.mu.Unlock()
+}
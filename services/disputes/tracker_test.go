@@ -0,0 +1,80 @@
+package disputes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonUnmarshal(data string, v interface{}) error {
+	return json.Unmarshal([]byte(data), v)
+}
+
+func disputeEvent(t *testing.T, id string, expiresAt time.Time) events.Event {
+	metadata := map[string]interface{}{}
+	assert.Nil(t, jsonUnmarshal(dispute, &metadata))
+	metadata["disputeId"] = id
+	metadata["expiresAt"] = expiresAt.Format(time.RFC3339)
+	return events.Event{Code: "HSD", CorrelationID: "order_id", Metadata: metadata}
+}
+
+func TestTracker(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	var received []Dispute
+	tracker := NewTracker(New(adapter, &am), func(d Dispute) { received = append(received, d) })
+	now := time.Date(2021, 3, 1, 20, 30, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	assert.True(t, tracker.HandleEvent(disputeEvent(t, "b", now.Add(5*time.Minute))))
+	assert.True(t, tracker.HandleEvent(disputeEvent(t, "a", now.Add(3*time.Minute))))
+	assert.True(t, tracker.HandleEvent(disputeEvent(t, "c", now.Add(time.Minute))))
+	assert.True(t, tracker.HandleEvent(disputeEvent(t, "d", now.Add(10*time.Minute))))
+	assert.False(t, tracker.HandleEvent(events.Event{Code: "HSD"}))
+	assert.False(t, tracker.HandleEvent(events.Event{Code: "PLACED"}))
+	assert.Equal(t, 4, len(received))
+	open := tracker.Open()
+	assert.Equal(t, []string{"c", "a", "b", "d"}, []string{open[0].ID, open[1].ID, open[2].ID, open[3].ID})
+
+	assert.Equal(t, ErrInvalidAlternative, tracker.Propose("a", Proposal{
+		AlternativeID: "alt_refund", Type: AlternativeRefund, Amount: &Amount{Value: 900},
+	}))
+	assert.Nil(t, tracker.Propose("a", Proposal{
+		AlternativeID: "alt_refund", Type: AlternativeRefund, Amount: &Amount{Value: 300},
+	}))
+	assert.Nil(t, tracker.Reject("b", "item entregue"))
+	assert.Equal(t, ErrUnknownDispute, tracker.Accept("b"))
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, ErrDisputeExpired, tracker.Accept("c"))
+	assert.Equal(t, []string{
+		"/v1.0/disputes/a/alternatives/alt_refund",
+		"/v1.0/disputes/b/reject",
+	}, paths)
+
+	// the counter-offer keeps the dispute open until it is settled
+	open = tracker.Open()
+	assert.Equal(t, []string{"a", "d"}, []string{open[0].ID, open[1].ID})
+
+	assert.True(t, tracker.HandleEvent(events.Event{Code: "HSS", Metadata: map[string]interface{}{"disputeId": "d"}}))
+	assert.False(t, tracker.HandleEvent(events.Event{Code: "HSS", Metadata: map[string]interface{}{"disputeId": "d"}}))
+	assert.True(t, tracker.HandleEvent(events.Event{Code: "HSS", Metadata: map[string]interface{}{"disputeId": "a"}}))
+	assert.Equal(t, 0, len(tracker.Open()))
+}
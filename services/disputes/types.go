@@ -0,0 +1,93 @@
+package disputes
+
+import "time"
+
+const (
+	// ActionCancellation the customer asks to cancel the whole order
+	ActionCancellation = "CANCELLATION"
+	// ActionPartialCancellation the customer asks to cancel some items
+	ActionPartialCancellation = "PARTIAL_CANCELLATION"
+	// ActionProposedAmountRefund the customer asks for a partial refund
+	ActionProposedAmountRefund = "PROPOSED_AMOUNT_REFUND"
+
+	// AlternativeRefund counter-offer of a smaller refund
+	AlternativeRefund = "REFUND"
+	// AlternativeAdditionalTime counter-offer of delivering within more time
+	AlternativeAdditionalTime = "ADDITIONAL_DELIVERY_TIME"
+
+	// TimeoutAcceptAutomatically the dispute is accepted when the deadline passes
+	TimeoutAcceptAutomatically = "ACCEPT_AUTOMATICALLY"
+	// TimeoutRejectAutomatically the dispute is rejected when the deadline passes
+	TimeoutRejectAutomatically = "REJECT_AUTOMATICALLY"
+)
+
+type (
+	// Dispute sent on the HANDSHAKE_DISPUTE event metadata
+	Dispute struct {
+		ID            string        `json:"disputeId"`
+		OrderID       string        `json:"orderId"`
+		Action        string        `json:"action"`
+		Message       string        `json:"message"`
+		HandshakeType string        `json:"handshakeType"`
+		TimeoutAction string        `json:"timeoutAction"`
+		CreatedAt     time.Time     `json:"createdAt"`
+		ExpiresAt     time.Time     `json:"expiresAt"`
+		Items         []Item        `json:"items,omitempty"`
+		Alternatives  []Alternative `json:"alternatives,omitempty"`
+		Evidences     []Evidence    `json:"media,omitempty"`
+	}
+
+	// Item disputed by the customer
+	Item struct {
+		Index        int    `json:"index"`
+		ExternalCode string `json:"externalCode,omitempty"`
+		Name         string `json:"name,omitempty"`
+		Quantity     int    `json:"quantity"`
+		Reason       string `json:"reason,omitempty"`
+		Amount       Amount `json:"amount"`
+	}
+
+	// Alternative the merchant may offer instead of accepting
+	Alternative struct {
+		ID       string              `json:"id"`
+		Type     string              `json:"type"`
+		Metadata AlternativeMetadata `json:"metadata"`
+	}
+
+	// AlternativeMetadata limits of an alternative
+	AlternativeMetadata struct {
+		MaxAmount                       *Amount `json:"maxAmount,omitempty"`
+		AllowedsAdditionalTimeInMinutes []int   `json:"allowedsAdditionalTimeInMinutes,omitempty"`
+	}
+
+	// Evidence photo sent by the customer
+	Evidence struct {
+		URL         string `json:"url"`
+		ContentType string `json:"contentType"`
+	}
+
+	// Amount in cents
+	Amount struct {
+		Value    int    `json:"value"`
+		Currency string `json:"currency,omitempty"`
+	}
+
+	// Proposal counter-offer made with one of the dispute alternatives
+	Proposal struct {
+		AlternativeID           string  `json:"-"`
+		Type                    string  `json:"type"`
+		Amount                  *Amount `json:"amount,omitempty"`
+		AdditionalTimeInMinutes int     `json:"additionalTimeInMinutes,omitempty"`
+		AdditionalTimeReason    string  `json:"additionalTimeReason,omitempty"`
+	}
+
+	rejection struct {
+		Reason string `json:"reason"`
+	}
+
+	apiError struct {
+		Details struct {
+			Code string `json:"code"`
+		} `json:"details"`
+	}
+)
@@ -58,6 +58,15 @@ func New(adapter adapters.Http, authService auth.Service) *eventService {
 	return &eventService{adapter, authService}
 }
 
+// Name returns the full event name (e.g. PLACED) whether
+// the API sent the full name or the short code (e.g. COL)
+func (e Event) Name() string {
+	if name, ok := ValidEventsByCodeName[e.Code]; ok {
+		return name
+	}
+	return e.Code
+}
+
 // Poll queries the iFood API for new events
 func (ev *eventService) Poll() (ml []Event, err error) {
 	err = ev.auth.Validate()
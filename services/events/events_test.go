@@ -266,3 +266,9 @@ func TestAcknowledge_StatusRequestEntityTooLarge(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "not get polled")
 }
+
+func TestEventName(t *testing.T) {
+	assert.Equal(t, "PLACED", Event{Code: "PLACED"}.Name())
+	assert.Equal(t, "RECOMMENDED_PREPARATION_START", Event{Code: "RPS"}.Name())
+	assert.Equal(t, "UNKNOWN", Event{Code: "UNKNOWN"}.Name())
+}
@@ -36,6 +36,8 @@ var ValidEventsByCodeName = map[string]string{
 	"UPT": "UPDATED",
 	"BOA": "BOX_ASSIGNED",
 	"RPS": "RECOMMENDED_PREPARATION_START",
+	"HSD": "HANDSHAKE_DISPUTE",
+	"HSS": "HANDSHAKE_SETTLEMENT",
 }
 
 // ValidEventsByNameCode API events by name:code
@@ -74,4 +76,6 @@ var ValidEventsByNameCode = map[string]string{
 	"UPDATED":                         "UPT",
 	"BOX_ASSIGNED":                    "BOA",
 	"RECOMMENDED_PREPARATION_START":   "RPS",
+	"HANDSHAKE_DISPUTE":               "HSD",
+	"HANDSHAKE_SETTLEMENT":            "HSS",
 }
@@ -0,0 +1,163 @@
+package merchant
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// AreaPolygon delivery area drawn as a polygon
+	AreaPolygon = "POLYGON"
+	// AreaRadius delivery area within a radius of the merchant
+	AreaRadius = "RADIUS"
+
+	// circleSegments vertices used to draw a radius area as GeoJSON
+	circleSegments = 64
+	earthRadius    = 6371008.8
+)
+
+// ErrInvalidDeliveryArea area without a valid polygon or radius
+var ErrInvalidDeliveryArea = errors.New("delivery area is invalid")
+
+type (
+	// DeliveryArea region the merchant delivers to
+	DeliveryArea struct {
+		ID             string       `json:"id"`
+		Name           string       `json:"name"`
+		Type           string       `json:"type"`
+		Center         *Coordinate  `json:"center,omitempty"`
+		RadiusInMeters float64      `json:"radius,omitempty"`
+		Polygon        []Coordinate `json:"polygon,omitempty"`
+		DeliveryFee    float64      `json:"deliveryFee"`
+		DeliveryTime   int          `json:"deliveryTime,omitempty"`
+	}
+
+	// Coordinate latitude and longitude in degrees
+	Coordinate struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	}
+
+	// FeatureCollection GeoJSON collection of delivery areas
+	FeatureCollection struct {
+		Type     string    `json:"type"`
+		Features []Feature `json:"features"`
+	}
+
+	// Feature GeoJSON delivery area
+	Feature struct {
+		Type       string                 `json:"type"`
+		ID         string                 `json:"id,omitempty"`
+		Geometry   Geometry               `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	// Geometry GeoJSON polygon, positions are [longitude, latitude]
+	Geometry struct {
+		Type        string         `json:"type"`
+		Coordinates [][][2]float64 `json:"coordinates"`
+	}
+)
+
+// Validate reports areas that can not be drawn
+func (a DeliveryArea) Validate() error {
+	switch a.Type {
+	case AreaPolygon:
+		if len(ring(a.Polygon)) < 4 {
+			return fmt.Errorf("%w: '%s' polygon needs at least 3 distinct points", ErrInvalidDeliveryArea, a.ID)
+		}
+	case AreaRadius:
+		if a.Center == nil || a.RadiusInMeters <= 0 {
+			return fmt.Errorf("%w: '%s' radius needs a center and a positive radius", ErrInvalidDeliveryArea, a.ID)
+		}
+	default:
+		return fmt.Errorf("%w: '%s' type '%s'", ErrInvalidDeliveryArea, a.ID, a.Type)
+	}
+	return nil
+}
+
+// Boundary returns the closed ring of the area, radius areas
+// are approximated by a polygon
+func (a DeliveryArea) Boundary() (boundary []Coordinate, err error) {
+	if err = a.Validate(); err != nil {
+		return
+	}
+	if a.Type == AreaPolygon {
+		return ring(a.Polygon), nil
+	}
+	for i := 0; i <= circleSegments; i++ {
+		bearing := 2 * math.Pi * float64(i%circleSegments) / circleSegments
+		boundary = append(boundary, destination(*a.Center, bearing, a.RadiusInMeters))
+	}
+	return
+}
+
+// GeoJSON returns the area as a GeoJSON feature
+func (a DeliveryArea) GeoJSON() (f Feature, err error) {
+	boundary, err := a.Boundary()
+	if err != nil {
+		return
+	}
+	positions := make([][2]float64, len(boundary))
+	for i, c := range boundary {
+		positions[i] = [2]float64{c.Longitude, c.Latitude}
+	}
+	properties := map[string]interface{}{
+		"name":        a.Name,
+		"type":        a.Type,
+		"deliveryFee": a.DeliveryFee,
+	}
+	if a.Type == AreaRadius {
+		properties["radius"] = a.RadiusInMeters
+	}
+	return Feature{
+		Type:       "Feature",
+		ID:         a.ID,
+		Geometry:   Geometry{Type: "Polygon", Coordinates: [][][2]float64{positions}},
+		Properties: properties,
+	}, nil
+}
+
+// DeliveryAreasGeoJSON returns the areas as a GeoJSON feature collection
+func DeliveryAreasGeoJSON(areas []DeliveryArea) (fc FeatureCollection, err error) {
+	fc = FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	for _, a := range areas {
+		f, err := a.GeoJSON()
+		if err != nil {
+			return fc, err
+		}
+		fc.Features = append(fc.Features, f)
+	}
+	return
+}
+
+// ring removes repeated points and closes the polygon
+func ring(polygon []Coordinate) (r []Coordinate) {
+	for _, c := range polygon {
+		if len(r) == 0 || r[len(r)-1] != c {
+			r = append(r, c)
+		}
+	}
+	if len(r) > 1 && r[0] == r[len(r)-1] {
+		r = r[:len(r)-1]
+	}
+	if len(r) < 3 {
+		return nil
+	}
+	return append(r, r[0])
+}
+
+// destination point at a distance and bearing (radians) from origin
+func destination(origin Coordinate, bearing, meters float64) Coordinate {
+	lat, lon := radians(origin.Latitude), radians(origin.Longitude)
+	angular := meters / earthRadius
+	destLat := math.Asin(math.Sin(lat)*math.Cos(angular) + math.Cos(lat)*math.Sin(angular)*math.Cos(bearing))
+	destLon := lon + math.Atan2(math.Sin(bearing)*math.Sin(angular)*math.Cos(lat),
+		math.Cos(angular)-math.Sin(lat)*math.Sin(destLat))
+	return Coordinate{Latitude: degrees(destLat), Longitude: degrees(destLon)}
+}
+
+func radians(d float64) float64 { return d * math.Pi / 180 }
+
+func degrees(r float64) float64 { return r * 180 / math.Pi }
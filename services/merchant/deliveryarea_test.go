@@ -0,0 +1,164 @@
+package merchant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+var merchantDetails = `{
+	"id": "merchant_id",
+	"name": "Pizzaria",
+	"corporateName": "Pizzaria LTDA",
+	"description": "Pizzas",
+	"averageTicket": 65.5,
+	"exclusive": false,
+	"type": "RESTAURANT",
+	"status": "AVAILABLE",
+	"createdAt": "2020-01-01T10:00:00Z",
+	"address": {"city": "Sao Paulo", "latitude": -23.55, "longitude": -46.63},
+	"operations": [{"name": "DELIVERY", "salesChannels": [{"name": "IFOOD_APP", "enabled": true}]}],
+	"logistics": [{"name": "IFOOD", "deliveredBy": "IFOOD", "enabled": true}]
+}`
+
+var merchantStatus = `[
+	{"operation": "DELIVERY", "salesChannel": "IFOOD_APP", "available": false, "state": "CLOSED",
+		"validations": [{"id": "opening-hours", "code": "outside.opening-hours.config", "state": "CLOSED"}],
+		"message": {"title": "Loja fechada"}}
+]`
+
+var deliveryAreas = `[
+	{"id": "area_1", "name": "Centro", "type": "POLYGON", "deliveryFee": 5,
+		"polygon": [
+			{"latitude": 0, "longitude": 0},
+			{"latitude": 0, "longitude": 1},
+			{"latitude": 1, "longitude": 1},
+			{"latitude": 1, "longitude": 0}
+		]},
+	{"id": "area_2", "name": "Raio", "type": "RADIUS", "deliveryFee": 8,
+		"center": {"latitude": -23.55, "longitude": -46.63}, "radius": 3000}
+]`
+
+func merchantServer(t *testing.T, path, body string) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, path, r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, body)
+		}),
+	)
+}
+
+func TestDetails_OK(t *testing.T) {
+	ts := merchantServer(t, "/merchant/v1.0/merchants/merchant_id", merchantDetails)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	md, err := New(adapter, &am).Details("merchant_id")
+	assert.Nil(t, err)
+	assert.Equal(t, "Pizzaria LTDA", md.CorporateName)
+	assert.Equal(t, 65.5, md.AverageTicket)
+	assert.Equal(t, -23.55, md.Address.Latitude)
+	assert.True(t, md.Operations[0].SalesChannels[0].Enabled)
+	assert.Equal(t, "IFOOD", md.Logistics[0].DeliveredBy)
+}
+
+func TestStatus_OK(t *testing.T) {
+	ts := merchantServer(t, "/merchant/v1.0/merchants/merchant_id/status", merchantStatus)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ms, err := New(adapter, &am).Status("merchant_id")
+	assert.Nil(t, err)
+	assert.Equal(t, StateClosed, ms[0].State)
+	assert.Equal(t, "outside.opening-hours.config", ms[0].Validations[0].Code)
+}
+
+func TestDeliveryAreas_OK(t *testing.T) {
+	ts := merchantServer(t, "/merchant/v1.0/merchants/merchant_id/delivery-areas", deliveryAreas)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	da, err := New(adapter, &am).DeliveryAreas("merchant_id")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(da))
+	assert.Equal(t, 4, len(da[0].Polygon))
+	assert.Equal(t, float64(3000), da[1].RadiusInMeters)
+}
+
+func TestMerchantEndpoints_Errors(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	merchantService := New(adapter, &am)
+	_, err := merchantService.Details("merchant_id")
+	assert.NotNil(t, err)
+	_, err = merchantService.Status("merchant_id")
+	assert.NotNil(t, err)
+	_, err = merchantService.DeliveryAreas("merchant_id")
+	assert.NotNil(t, err)
+	_, err = merchantService.DeliveryAreas("")
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+}
+
+func TestDeliveryArea_Validate(t *testing.T) {
+	assert.True(t, errors.Is(DeliveryArea{Type: AreaPolygon, Polygon: []Coordinate{{0, 0}, {1, 1}, {0, 0}}}.Validate(),
+		ErrInvalidDeliveryArea))
+	assert.True(t, errors.Is(DeliveryArea{Type: AreaRadius, RadiusInMeters: 10}.Validate(), ErrInvalidDeliveryArea))
+	assert.True(t, errors.Is(DeliveryArea{Type: "HEXAGON"}.Validate(), ErrInvalidDeliveryArea))
+}
+
+func TestDeliveryAreasGeoJSON(t *testing.T) {
+	areas := []DeliveryArea{}
+	assert.Nil(t, json.Unmarshal([]byte(deliveryAreas), &areas))
+	fc, err := DeliveryAreasGeoJSON(areas)
+	assert.Nil(t, err)
+	assert.Equal(t, "FeatureCollection", fc.Type)
+	assert.Equal(t, 2, len(fc.Features))
+
+	polygon := fc.Features[0]
+	assert.Equal(t, "area_1", polygon.ID)
+	ring := polygon.Geometry.Coordinates[0]
+	assert.Equal(t, 5, len(ring))
+	assert.Equal(t, ring[0], ring[4])
+	assert.Equal(t, [2]float64{1, 0}, ring[1])
+
+	circle := fc.Features[1]
+	ring = circle.Geometry.Coordinates[0]
+	assert.Equal(t, circleSegments+1, len(ring))
+	assert.Equal(t, ring[0], ring[circleSegments])
+	// first vertex is due north of the center, 3km away
+	assert.InDelta(t, -46.63, ring[0][0], 1e-9)
+	assert.InDelta(t, 3000/earthRadius*180/math.Pi, ring[0][1]+23.55, 1e-9)
+	assert.Equal(t, float64(3000), circle.Properties["radius"])
+
+	out, err := json.Marshal(fc)
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), `"type":"Polygon"`)
+
+	_, err = DeliveryAreasGeoJSON([]DeliveryArea{{ID: "bad", Type: AreaRadius}})
+	assert.NotNil(t, err)
+}
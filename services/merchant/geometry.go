@@ -0,0 +1,109 @@
+package merchant
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoDeliveryAreas no area to check against
+var ErrNoDeliveryAreas = errors.New("merchant has no delivery areas")
+
+// AreaMatch result of locating a point among delivery areas
+//
+// when outside, Area is the closest area and DistanceInMeters
+// how far the point is from its border
+type AreaMatch struct {
+	Inside           bool
+	Area             DeliveryArea
+	DistanceInMeters float64
+}
+
+// Contains reports if the point is inside the area
+func (a DeliveryArea) Contains(point Coordinate) (inside bool, err error) {
+	distance, err := a.DistanceOutside(point)
+	return (err == nil) && (distance == 0), err
+}
+
+// DistanceOutside returns how far, in meters, the point is from
+// the area border; points inside the area are at 0
+func (a DeliveryArea) DistanceOutside(point Coordinate) (meters float64, err error) {
+	if err = a.Validate(); err != nil {
+		return
+	}
+	if a.Type == AreaRadius {
+		return math.Max(0, haversine(*a.Center, point)-a.RadiusInMeters), nil
+	}
+	polygon := ring(a.Polygon)
+	if inPolygon(polygon, point) {
+		return 0, nil
+	}
+	meters = math.Inf(1)
+	for i := 0; i < len(polygon)-1; i++ {
+		meters = math.Min(meters, segmentDistance(point, polygon[i], polygon[i+1]))
+	}
+	return
+}
+
+// Locate returns the area containing the point, or the closest one
+func Locate(areas []DeliveryArea, point Coordinate) (match AreaMatch, err error) {
+	if len(areas) == 0 {
+		err = ErrNoDeliveryAreas
+		return
+	}
+	match.DistanceInMeters = math.Inf(1)
+	for _, a := range areas {
+		distance, err := a.DistanceOutside(point)
+		if err != nil {
+			return AreaMatch{}, err
+		}
+		if distance < match.DistanceInMeters {
+			match = AreaMatch{Inside: distance == 0, Area: a, DistanceInMeters: distance}
+		}
+		if match.Inside {
+			return match, nil
+		}
+	}
+	return
+}
+
+// inPolygon ray casting on a closed ring
+func inPolygon(polygon []Coordinate, point Coordinate) (inside bool) {
+	for i := 0; i < len(polygon)-1; i++ {
+		a, b := polygon[i], polygon[i+1]
+		if (a.Latitude > point.Latitude) == (b.Latitude > point.Latitude) {
+			continue
+		}
+		crossing := a.Longitude + (point.Latitude-a.Latitude)/(b.Latitude-a.Latitude)*(b.Longitude-a.Longitude)
+		if point.Longitude < crossing {
+			inside = !inside
+		}
+	}
+	return
+}
+
+// segmentDistance distance in meters from the point to the segment a-b,
+// projected on a plane tangent to the point
+func segmentDistance(point, a, b Coordinate) float64 {
+	scale := earthRadius * math.Pi / 180
+	project := func(c Coordinate) (x, y float64) {
+		return (c.Longitude - point.Longitude) * scale * math.Cos(radians(point.Latitude)),
+			(c.Latitude - point.Latitude) * scale
+	}
+	ax, ay := project(a)
+	bx, by := project(b)
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/length))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
+
+// haversine great circle distance in meters
+func haversine(a, b Coordinate) float64 {
+	dLat := radians(b.Latitude - a.Latitude)
+	dLon := radians(b.Longitude - a.Longitude)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(a.Latitude))*math.Cos(radians(b.Latitude))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
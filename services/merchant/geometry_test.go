@@ -0,0 +1,69 @@
+package merchant
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// square of about 1.1km around the equator origin
+var square = DeliveryArea{ID: "square", Type: AreaPolygon, Polygon: []Coordinate{
+	{0, 0}, {0, 0.01}, {0.01, 0.01}, {0.01, 0},
+}}
+
+func TestDeliveryArea_ContainsPolygon(t *testing.T) {
+	inside, err := square.Contains(Coordinate{0.005, 0.005})
+	assert.Nil(t, err)
+	assert.True(t, inside)
+	inside, err = square.Contains(Coordinate{0.005, 0.02})
+	assert.Nil(t, err)
+	assert.False(t, inside)
+
+	concave := DeliveryArea{Type: AreaPolygon, Polygon: []Coordinate{
+		{0, 0}, {0, 3}, {3, 3}, {3, 2}, {1, 2}, {1, 1}, {3, 1}, {3, 0},
+	}}
+	inside, _ = concave.Contains(Coordinate{2, 1.5})
+	assert.False(t, inside)
+	inside, _ = concave.Contains(Coordinate{0.5, 1.5})
+	assert.True(t, inside)
+}
+
+func TestDeliveryArea_DistanceOutside(t *testing.T) {
+	// 0.01 degree of longitude on the equator is about 1112m
+	meters, err := square.DistanceOutside(Coordinate{0.005, 0.02})
+	assert.Nil(t, err)
+	assert.InDelta(t, 1112, meters, 2)
+	meters, _ = square.DistanceOutside(Coordinate{0.005, 0.005})
+	assert.Equal(t, float64(0), meters)
+	// corner distance
+	meters, _ = square.DistanceOutside(Coordinate{-0.01, -0.01})
+	assert.InDelta(t, 1572, meters, 3)
+
+	radius := DeliveryArea{Type: AreaRadius, Center: &Coordinate{0, 0}, RadiusInMeters: 1000}
+	meters, err = radius.DistanceOutside(Coordinate{0, 0.02})
+	assert.Nil(t, err)
+	assert.InDelta(t, 1224, meters, 2)
+	inside, _ := radius.Contains(Coordinate{0, 0.005})
+	assert.True(t, inside)
+
+	_, err = DeliveryArea{Type: AreaRadius}.Contains(Coordinate{})
+	assert.True(t, errors.Is(err, ErrInvalidDeliveryArea))
+}
+
+func TestLocate(t *testing.T) {
+	far := DeliveryArea{ID: "far", Type: AreaRadius, Center: &Coordinate{1, 1}, RadiusInMeters: 500}
+	match, err := Locate([]DeliveryArea{far, square}, Coordinate{0.005, 0.005})
+	assert.Nil(t, err)
+	assert.True(t, match.Inside)
+	assert.Equal(t, "square", match.Area.ID)
+
+	match, err = Locate([]DeliveryArea{far, square}, Coordinate{0.005, 0.02})
+	assert.Nil(t, err)
+	assert.False(t, match.Inside)
+	assert.Equal(t, "square", match.Area.ID)
+	assert.InDelta(t, 1112, match.DistanceInMeters, 2)
+
+	_, err = Locate(nil, Coordinate{})
+	assert.Equal(t, ErrNoDeliveryAreas, err)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
@@ -22,6 +23,8 @@ var (
 	ErrMerchantNotSpecified = errors.New("merchant not specified")
 	// ErrMerchantORUnavailabilityIDNotSpecified no merchant or unavailability
 	ErrMerchantORUnavailabilityIDNotSpecified = errors.New("merchant or unavailability not specified")
+	// ErrInvalidUnavailabilityPeriod unavailability ending before it starts
+	ErrInvalidUnavailabilityPeriod = errors.New("unavailability end should be after its start")
 )
 
 type (
@@ -30,8 +33,14 @@ type (
 		ListAll() ([]Merchant, error)
 		Unavailabilities(merchantUUID string) (Unavailabilities, error)
 		CreateUnavailabilityNow(merchantUUID, description string, pauseMinutes int32) (UnavailabilityResponse, error)
+		CreateUnavailability(merchantUUID, description string, start, end time.Time) (UnavailabilityResponse, error)
 		DeleteUnavailability(merchantUUID, unavailabilityID string) error
 		Availability(merchantUUID string) (AvailabilityResponse, error)
+		OpeningHours(merchantUUID string) (WeeklySchedule, error)
+		UpdateOpeningHours(merchantUUID string, schedule WeeklySchedule) error
+		Details(merchantUUID string) (MerchantDetails, error)
+		Status(merchantUUID string) ([]OperationStatus, error)
+		DeliveryAreas(merchantUUID string) ([]DeliveryArea, error)
 	}
 
 	merchantService struct {
@@ -134,6 +143,52 @@ func (m *merchantService) CreateUnavailabilityNow(merchantUUID, description stri
 	return ur, json.Unmarshal(resp, &ur)
 }
 
+// CreateUnavailability cadastra indisponibilidade no merchant com inicio e fim definidos
+//
+// used to plan pauses ahead, like holidays and maintenance
+func (m *merchantService) CreateUnavailability(merchantUUID, description string, start, end time.Time) (ur UnavailabilityResponse, err error) {
+	if merchantUUID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Merchant CreateUnavailability: ", err.Error())
+		return
+	}
+	if !end.After(start) {
+		err = ErrInvalidUnavailabilityPeriod
+		glg.Error("[SDK] Merchant CreateUnavailability: ", err.Error())
+		return
+	}
+	if err = m.auth.Validate(); err != nil {
+		glg.Error("[SDK] Merchant CreateUnavailability auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s/unavailabilities", v1Endpoint, merchantUUID)
+	unv := scheduledUnavailability{
+		Description: description,
+		Start:       start.Format(time.RFC3339),
+		End:         end.Format(time.RFC3339),
+	}
+	reader, err := httpadapter.NewJsonReader(unv)
+	if err != nil {
+		glg.Error("[SDK] Merchant CreateUnavailability NewJsonReader error: ", err.Error())
+		return
+	}
+	resp, status, err := m.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	if err != nil {
+		glg.Error("[SDK] Merchant CreateUnavailability adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if (status != http.StatusOK) && (status != http.StatusCreated) {
+		glg.Error("[SDK] Merchant CreateUnavailability status code: ", status, " merchant: ", merchantUUID)
+		err = fmt.Errorf("Merchant '%s' could not create 'unavailability'", merchantUUID)
+		glg.Error("[SDK] Merchant CreateUnavailability err: ", err)
+		return
+	}
+	return ur, json.Unmarshal(resp, &ur)
+}
+
 // DeleteUnavailability remove indisponibilidade no merchant
 func (m *merchantService) DeleteUnavailability(merchantUUID, unavailabilityID string) (err error) {
 	if (merchantUUID == "") || (unavailabilityID == "") {
@@ -191,3 +246,160 @@ func (m *merchantService) Availability(merchantUUID string) (ar AvailabilityResp
 	}
 	return ar, json.Unmarshal(resp, &ar)
 }
+
+// OpeningHours recebe os horarios de funcionamento semanais do merchant
+func (m *merchantService) OpeningHours(merchantUUID string) (ws WeeklySchedule, err error) {
+	if merchantUUID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Merchant OpeningHours: ", err.Error())
+		return
+	}
+	if err = m.auth.Validate(); err != nil {
+		glg.Error("[SDK] Merchant OpeningHours auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
+	endpoint := fmt.Sprintf("/merchant%s/%s/opening-hours", v1Endpoint, merchantUUID)
+	resp, status, err := m.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Merchant OpeningHours adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Merchant OpeningHours status code: ", status, " merchant: ", merchantUUID)
+		err = fmt.Errorf("Merchant '%s' could not get opening hours", merchantUUID)
+		glg.Error("[SDK] Merchant OpeningHours err: ", err)
+		return
+	}
+	oh := OpeningHours{}
+	if err = json.Unmarshal(resp, &oh); err != nil {
+		glg.Error("[SDK] Merchant OpeningHours Unmarshal: ", err.Error())
+		return
+	}
+	return ScheduleFromShifts(oh.Shifts)
+}
+
+// UpdateOpeningHours substitui os horarios de funcionamento semanais do merchant
+func (m *merchantService) UpdateOpeningHours(merchantUUID string, schedule WeeklySchedule) (err error) {
+	if merchantUUID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Merchant UpdateOpeningHours: ", err.Error())
+		return
+	}
+	if err = schedule.Validate(); err != nil {
+		glg.Error("[SDK] Merchant UpdateOpeningHours Validate: ", err.Error())
+		return
+	}
+	if err = m.auth.Validate(); err != nil {
+		glg.Error("[SDK] Merchant UpdateOpeningHours auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
+	endpoint := fmt.Sprintf("/merchant%s/%s/opening-hours", v1Endpoint, merchantUUID)
+	reader, err := httpadapter.NewJsonReader(OpeningHours{StoreID: merchantUUID, Shifts: schedule.Shifts()})
+	if err != nil {
+		glg.Error("[SDK] Merchant UpdateOpeningHours NewJsonReader error: ", err.Error())
+		return
+	}
+	_, status, err := m.adapter.DoRequest(http.MethodPut, endpoint, reader, headers)
+	if err != nil {
+		glg.Error("[SDK] Merchant UpdateOpeningHours adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if (status != http.StatusOK) && (status != http.StatusCreated) {
+		glg.Error("[SDK] Merchant UpdateOpeningHours status code: ", status, " merchant: ", merchantUUID)
+		err = fmt.Errorf("Merchant '%s' could not update opening hours", merchantUUID)
+		glg.Error("[SDK] Merchant UpdateOpeningHours err: ", err)
+		return
+	}
+	return
+}
+
+// Details recebe o cadastro completo do merchant
+func (m *merchantService) Details(merchantUUID string) (md MerchantDetails, err error) {
+	if merchantUUID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Merchant Details: ", err.Error())
+		return
+	}
+	if err = m.auth.Validate(); err != nil {
+		glg.Error("[SDK] Merchant Details auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
+	endpoint := fmt.Sprintf("/merchant%s/%s", v1Endpoint, merchantUUID)
+	resp, status, err := m.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Merchant Details adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Merchant Details status code: ", status, " merchant: ", merchantUUID)
+		err = fmt.Errorf("Merchant '%s' could not get details", merchantUUID)
+		glg.Error("[SDK] Merchant Details err: ", err)
+		return
+	}
+	return md, json.Unmarshal(resp, &md)
+}
+
+// Status recebe a disponibilidade de cada operacao do merchant
+func (m *merchantService) Status(merchantUUID string) (ms []OperationStatus, err error) {
+	if merchantUUID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Merchant Status: ", err.Error())
+		return
+	}
+	if err = m.auth.Validate(); err != nil {
+		glg.Error("[SDK] Merchant Status auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
+	endpoint := fmt.Sprintf("/merchant%s/%s/status", v1Endpoint, merchantUUID)
+	resp, status, err := m.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Merchant Status adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Merchant Status status code: ", status, " merchant: ", merchantUUID)
+		err = fmt.Errorf("Merchant '%s' could not get status", merchantUUID)
+		glg.Error("[SDK] Merchant Status err: ", err)
+		return
+	}
+	return ms, json.Unmarshal(resp, &ms)
+}
+
+// DeliveryAreas recebe as areas de entrega do merchant
+//
+// use DeliveryAreasGeoJSON to render them
+func (m *merchantService) DeliveryAreas(merchantUUID string) (da []DeliveryArea, err error) {
+	if merchantUUID == "" {
+		err = ErrMerchantNotSpecified
+		glg.Error("[SDK] Merchant DeliveryAreas: ", err.Error())
+		return
+	}
+	if err = m.auth.Validate(); err != nil {
+		glg.Error("[SDK] Merchant DeliveryAreas auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
+	endpoint := fmt.Sprintf("/merchant%s/%s/delivery-areas", v1Endpoint, merchantUUID)
+	resp, status, err := m.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Merchant DeliveryAreas adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Merchant DeliveryAreas status code: ", status, " merchant: ", merchantUUID)
+		err = fmt.Errorf("Merchant '%s' could not get delivery areas", merchantUUID)
+		glg.Error("[SDK] Merchant DeliveryAreas err: ", err)
+		return
+	}
+	return da, json.Unmarshal(resp, &da)
+}
@@ -0,0 +1,153 @@
+package merchant
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kpango/glg"
+)
+
+const (
+	// StateOK the store is open
+	StateOK = "OK"
+	// StateWarning the store is open with restrictions
+	StateWarning = "WARNING"
+	// StateClosed the store is closed
+	StateClosed = "CLOSED"
+	// StateError the store is closed by an error
+	StateError = "ERROR"
+)
+
+type (
+	// AvailabilityChange is handed to the callbacks when a store
+	// context goes from available to closed or error
+	AvailabilityChange struct {
+		MerchantID    string
+		Context       string
+		PreviousState string
+		State         string
+		Message       Message
+		Failing       []FailingValidation
+		At            time.Time
+	}
+
+	// FailingValidation validation not OK behind a change
+	FailingValidation struct {
+		Code  string
+		State string
+		Title string
+	}
+
+	// Monitor polls the availability of a set of merchants and
+	// keeps their previous state to detect closings
+	Monitor struct {
+		service   Service
+		merchants []string
+		now       func() time.Time
+		mu        sync.Mutex
+		previous  map[string]map[string]Availability
+		callbacks []func(AvailabilityChange)
+	}
+)
+
+// NewMonitor returns an availability monitor for the merchants
+func NewMonitor(service Service, merchantUUIDs ...string) *Monitor {
+	return &Monitor{
+		service:   service,
+		merchants: merchantUUIDs,
+		now:       time.Now,
+		previous:  make(map[string]map[string]Availability),
+	}
+}
+
+// OnChange registers a callback for availability changes
+func (m *Monitor) OnChange(callback func(AvailabilityChange)) {
+	m.mu.Lock()
+	m.callbacks = append(m.callbacks, callback)
+	m.mu.Unlock()
+}
+
+// State returns the last availability read for a merchant
+func (m *Monitor) State(merchantUUID string) (ar AvailabilityResponse, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	contexts, ok := m.previous[merchantUUID]
+	for _, a := range contexts {
+		ar = append(ar, a)
+	}
+	sort.Slice(ar, func(i, j int) bool { return ar[i].Context < ar[j].Context })
+	return
+}
+
+// Check reads the availability of every merchant once, returning the last
+// error; merchants that could not be read keep their previous state
+func (m *Monitor) Check() (err error) {
+	for _, merchantUUID := range m.merchants {
+		ar, availabilityErr := m.service.Availability(merchantUUID)
+		if availabilityErr != nil {
+			glg.Error("[SDK] Merchant Monitor Availability: ", availabilityErr.Error(), " merchant: ", merchantUUID)
+			err = availabilityErr
+			continue
+		}
+		m.update(merchantUUID, ar)
+	}
+	return
+}
+
+// Run checks the merchants every interval until stop is closed
+func (m *Monitor) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
+
+func (m *Monitor) update(merchantUUID string, ar AvailabilityResponse) {
+	var changes []AvailabilityChange
+	m.mu.Lock()
+	previous := m.previous[merchantUUID]
+	current := make(map[string]Availability)
+	for _, a := range ar {
+		current[a.Context] = a
+		before, ok := previous[a.Context]
+		if ok && before.Available && closed(a) {
+			changes = append(changes, AvailabilityChange{
+				MerchantID:    merchantUUID,
+				Context:       a.Context,
+				PreviousState: before.State,
+				State:         a.State,
+				Message:       a.Message,
+				Failing:       failing(a.Validations),
+				At:            m.now(),
+			})
+		}
+	}
+	m.previous[merchantUUID] = current
+	callbacks := append([]func(AvailabilityChange){}, m.callbacks...)
+	m.mu.Unlock()
+	for _, change := range changes {
+		for _, callback := range callbacks {
+			callback(change)
+		}
+	}
+}
+
+func closed(a Availability) bool {
+	return !a.Available || (a.State == StateClosed) || (a.State == StateError)
+}
+
+func failing(validations []Validation) (f []FailingValidation) {
+	for _, v := range validations {
+		if v.State != StateOK {
+			f = append(f, FailingValidation{Code: v.Code, State: v.State, Title: v.Message.Title})
+		}
+	}
+	return
+}
@@ -0,0 +1,115 @@
+package merchant
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+const closedAvailability = `[
+	{
+		"context": "delivery",
+		"available": false,
+		"state": "CLOSED",
+		"validations": [
+			{"id": "opening-hours", "code": "outside.opening-hours.config", "state": "CLOSED",
+				"message": {"title": "Fora do horario de funcionamento", "priority": 1}},
+			{"id": "unavailabilities", "code": "no.unavailabilities", "state": "OK",
+				"message": {"title": "Sem pausas", "priority": 2}}
+		],
+		"message": {"title": "Loja fechada", "priority": 1}
+	}
+]`
+
+func TestMonitor_Check(t *testing.T) {
+	responses := map[string][]string{
+		"a": {available, closedAvailability, closedAvailability, available},
+		"b": {closedAvailability, closedAvailability, available, available},
+	}
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			merchantID := strings.Split(r.URL.Path, "/")[4]
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, responses[merchantID][0])
+			responses[merchantID] = responses[merchantID][1:]
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	monitor := NewMonitor(New(adapter, &am), "a", "b")
+	now := time.Date(2021, 3, 1, 20, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+	var changes []AvailabilityChange
+	monitor.OnChange(func(c AvailabilityChange) { changes = append(changes, c) })
+
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, monitor.Check())
+	}
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, "a", changes[0].MerchantID)
+	assert.Equal(t, "delivery", changes[0].Context)
+	assert.Equal(t, StateOK, changes[0].PreviousState)
+	assert.Equal(t, StateClosed, changes[0].State)
+	assert.Equal(t, now, changes[0].At)
+	assert.Equal(t, []FailingValidation{
+		{Code: "outside.opening-hours.config", State: StateClosed, Title: "Fora do horario de funcionamento"},
+	}, changes[0].Failing)
+
+	ar, ok := monitor.State("b")
+	assert.True(t, ok)
+	assert.True(t, ar[0].Available)
+}
+
+func TestMonitor_CheckErr(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	monitor := NewMonitor(New(adapter, &am), "down")
+	assert.NotNil(t, monitor.Check())
+	_, ok := monitor.State("down")
+	assert.False(t, ok)
+}
+
+func TestMonitor_Run(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, available)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	monitor := NewMonitor(New(adapter, &am), "a")
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		monitor.Run(time.Millisecond, stop)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool {
+		_, ok := monitor.State("a")
+		return ok
+	}, time.Second, time.Millisecond)
+	close(stop)
+	<-done
+}
@@ -0,0 +1,200 @@
+package merchant
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+var (
+	// ErrInvalidInterval opening interval with invalid start or duration
+	ErrInvalidInterval = errors.New("opening interval is invalid")
+	// ErrOverlappingIntervals opening intervals overlap
+	ErrOverlappingIntervals = errors.New("opening intervals overlap")
+
+	// apiWeekdays day of week names used by the API
+	apiWeekdays = map[time.Weekday]string{
+		time.Sunday:    "SUNDAY",
+		time.Monday:    "MONDAY",
+		time.Tuesday:   "TUESDAY",
+		time.Wednesday: "WEDNESDAY",
+		time.Thursday:  "THURSDAY",
+		time.Friday:    "FRIDAY",
+		time.Saturday:  "SATURDAY",
+	}
+)
+
+type (
+	// WeeklySchedule opening intervals by day of the week
+	WeeklySchedule map[time.Weekday][]Interval
+
+	// Interval the store is open, Start is the time of day it opens;
+	// intervals may run past midnight into the next day
+	Interval struct {
+		Start    time.Duration
+		Duration time.Duration
+	}
+
+	// OpeningHours API shape of the weekly opening hours
+	OpeningHours struct {
+		StoreID string  `json:"storeId,omitempty"`
+		Shifts  []Shift `json:"shifts"`
+	}
+
+	// Shift opening interval as sent by the API, duration in minutes
+	Shift struct {
+		ID        string `json:"id,omitempty"`
+		DayOfWeek string `json:"dayOfWeek"`
+		Start     string `json:"start"`
+		Duration  int    `json:"duration"`
+	}
+)
+
+// NewInterval returns the interval between two "HH:MM" times of day,
+// an end before the start closes on the next day
+func NewInterval(start, end string) (i Interval, err error) {
+	startAt, err := parseClock(start)
+	if err != nil {
+		return
+	}
+	endAt, err := parseClock(end)
+	if err != nil {
+		return
+	}
+	duration := endAt - startAt
+	if duration <= 0 {
+		duration += day
+	}
+	return Interval{Start: startAt, Duration: duration}, nil
+}
+
+// End time of day the interval closes, may be past 24h
+func (i Interval) End() time.Duration {
+	return i.Start + i.Duration
+}
+
+// String formats the interval as "HH:MM-HH:MM"
+func (i Interval) String() string {
+	return formatClock(i.Start) + "-" + formatClock(i.End()%day)
+}
+
+// Validate reports invalid intervals and intervals overlapping each other,
+// also across days
+func (ws WeeklySchedule) Validate() error {
+	type span struct {
+		weekday  time.Weekday
+		interval Interval
+		from, to time.Duration
+	}
+	var spans []span
+	for weekday, intervals := range ws {
+		if _, ok := apiWeekdays[weekday]; !ok {
+			return fmt.Errorf("%w: day %d", ErrInvalidInterval, weekday)
+		}
+		for _, i := range intervals {
+			if (i.Start < 0) || (i.Start >= day) || (i.Duration <= 0) || (i.Duration > day) ||
+				(i.Start%time.Minute != 0) || (i.Duration%time.Minute != 0) {
+				return fmt.Errorf("%w: %s %s", ErrInvalidInterval, weekday, i)
+			}
+			from := time.Duration(weekday)*day + i.Start
+			spans = append(spans, span{weekday, i, from, from + i.Duration})
+		}
+	}
+	if len(spans) < 2 {
+		return nil
+	}
+	sort.Slice(spans, func(a, b int) bool { return spans[a].from < spans[b].from })
+	for n := range spans {
+		current, next := spans[n], spans[(n+1)%len(spans)]
+		nextFrom := next.from
+		if n == len(spans)-1 {
+			nextFrom += week
+		}
+		if current.to > nextFrom {
+			return fmt.Errorf("%w: %s %s and %s %s", ErrOverlappingIntervals,
+				current.weekday, current.interval, next.weekday, next.interval)
+		}
+	}
+	return nil
+}
+
+// IsOpen reports if t falls in one of the intervals
+func (ws WeeklySchedule) IsOpen(t time.Time) bool {
+	at := time.Duration(t.Weekday())*day +
+		time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	for weekday, intervals := range ws {
+		for _, i := range intervals {
+			from := time.Duration(weekday)*day + i.Start
+			to := from + i.Duration
+			if (at >= from && at < to) || (at+week >= from && at+week < to) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Shifts converts the schedule to the API shape, ordered from sunday
+func (ws WeeklySchedule) Shifts() (shifts []Shift) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		intervals := append([]Interval{}, ws[weekday]...)
+		sort.Slice(intervals, func(a, b int) bool { return intervals[a].Start < intervals[b].Start })
+		for _, i := range intervals {
+			shifts = append(shifts, Shift{
+				DayOfWeek: apiWeekdays[weekday],
+				Start:     formatClock(i.Start) + ":00",
+				Duration:  int(i.Duration / time.Minute),
+			})
+		}
+	}
+	return
+}
+
+// ScheduleFromShifts converts the API shape to a validated schedule
+func ScheduleFromShifts(shifts []Shift) (ws WeeklySchedule, err error) {
+	ws = make(WeeklySchedule)
+	for _, shift := range shifts {
+		weekday, ok := parseWeekday(shift.DayOfWeek)
+		if !ok {
+			return nil, fmt.Errorf("%w: day of week '%s'", ErrInvalidInterval, shift.DayOfWeek)
+		}
+		start, err := parseClock(shift.Start)
+		if err != nil {
+			return nil, err
+		}
+		ws[weekday] = append(ws[weekday], Interval{Start: start, Duration: time.Duration(shift.Duration) * time.Minute})
+	}
+	return ws, ws.Validate()
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for weekday, apiName := range apiWeekdays {
+		if strings.EqualFold(apiName, name) {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses "HH:MM" or "HH:MM:SS" as the time since midnight
+func parseClock(value string) (d time.Duration, err error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		t, parseErr := time.Parse(layout, value)
+		if parseErr == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: time '%s' should be HH:MM", ErrInvalidInterval, value)
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
@@ -0,0 +1,171 @@
+package merchant
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+var openingHours = `{
+	"storeId": "merchant_id",
+	"shifts": [
+		{"id": "1", "dayOfWeek": "MONDAY", "start": "11:00:00", "duration": 240},
+		{"id": "2", "dayOfWeek": "MONDAY", "start": "18:00:00", "duration": 360},
+		{"id": "3", "dayOfWeek": "SUNDAY", "start": "22:00:00", "duration": 180}
+	]
+}`
+
+func mustInterval(t *testing.T, start, end string) Interval {
+	i, err := NewInterval(start, end)
+	assert.Nil(t, err)
+	return i
+}
+
+func TestNewInterval(t *testing.T) {
+	i := mustInterval(t, "18:00", "02:30")
+	assert.Equal(t, 18*time.Hour, i.Start)
+	assert.Equal(t, 8*time.Hour+30*time.Minute, i.Duration)
+	assert.Equal(t, "18:00-02:30", i.String())
+	assert.Equal(t, 24*time.Hour, mustInterval(t, "00:00", "00:00").Duration)
+	_, err := NewInterval("25:00", "10:00")
+	assert.True(t, errors.Is(err, ErrInvalidInterval))
+}
+
+func TestWeeklySchedule_Validate(t *testing.T) {
+	ws := WeeklySchedule{
+		time.Monday:  {mustInterval(t, "11:00", "15:00"), mustInterval(t, "18:00", "23:00")},
+		time.Tuesday: {mustInterval(t, "11:00", "15:00")},
+	}
+	assert.Nil(t, ws.Validate())
+
+	ws[time.Monday] = append(ws[time.Monday], mustInterval(t, "14:00", "16:00"))
+	assert.True(t, errors.Is(ws.Validate(), ErrOverlappingIntervals))
+
+	overnight := WeeklySchedule{
+		time.Monday:  {mustInterval(t, "20:00", "02:00")},
+		time.Tuesday: {mustInterval(t, "01:00", "10:00")},
+	}
+	assert.True(t, errors.Is(overnight.Validate(), ErrOverlappingIntervals))
+
+	wrap := WeeklySchedule{
+		time.Saturday: {mustInterval(t, "20:00", "03:00")},
+		time.Sunday:   {mustInterval(t, "02:00", "10:00")},
+	}
+	assert.True(t, errors.Is(wrap.Validate(), ErrOverlappingIntervals))
+
+	invalid := WeeklySchedule{time.Monday: {{Start: 10 * time.Hour}}}
+	assert.True(t, errors.Is(invalid.Validate(), ErrInvalidInterval))
+	invalid = WeeklySchedule{time.Weekday(9): {mustInterval(t, "10:00", "11:00")}}
+	assert.True(t, errors.Is(invalid.Validate(), ErrInvalidInterval))
+}
+
+func TestWeeklySchedule_IsOpen(t *testing.T) {
+	ws := WeeklySchedule{time.Saturday: {mustInterval(t, "20:00", "02:00")}}
+	// 2021-03-06 is a saturday
+	assert.True(t, ws.IsOpen(time.Date(2021, 3, 6, 21, 0, 0, 0, time.UTC)))
+	assert.True(t, ws.IsOpen(time.Date(2021, 3, 7, 1, 59, 0, 0, time.UTC)))
+	assert.False(t, ws.IsOpen(time.Date(2021, 3, 7, 2, 0, 0, 0, time.UTC)))
+	assert.False(t, ws.IsOpen(time.Date(2021, 3, 6, 19, 0, 0, 0, time.UTC)))
+}
+
+func TestWeeklySchedule_Shifts(t *testing.T) {
+	ws := WeeklySchedule{
+		time.Monday: {mustInterval(t, "18:00", "00:00"), mustInterval(t, "11:00", "15:00")},
+		time.Sunday: {mustInterval(t, "22:00", "01:00")},
+	}
+	shifts := ws.Shifts()
+	assert.Equal(t, []Shift{
+		{DayOfWeek: "SUNDAY", Start: "22:00:00", Duration: 180},
+		{DayOfWeek: "MONDAY", Start: "11:00:00", Duration: 240},
+		{DayOfWeek: "MONDAY", Start: "18:00:00", Duration: 360},
+	}, shifts)
+	back, err := ScheduleFromShifts(shifts)
+	assert.Nil(t, err)
+	assert.Equal(t, shifts, back.Shifts())
+
+	_, err = ScheduleFromShifts([]Shift{{DayOfWeek: "HOLIDAY", Start: "10:00:00", Duration: 60}})
+	assert.True(t, errors.Is(err, ErrInvalidInterval))
+}
+
+func TestOpeningHours_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/merchant/v1.0/merchants/merchant_id/opening-hours", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, openingHours)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	merchantService := New(adapter, &am)
+	ws, err := merchantService.OpeningHours("merchant_id")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ws[time.Monday]))
+	assert.Equal(t, "22:00-01:00", ws[time.Sunday][0].String())
+}
+
+func TestOpeningHours_StatusBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	merchantService := New(adapter, &am)
+	_, err := merchantService.OpeningHours("merchant_id")
+	assert.NotNil(t, err)
+	_, err = merchantService.OpeningHours("")
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+}
+
+func TestUpdateOpeningHours_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/merchant/v1.0/merchants/merchant_id/opening-hours", r.URL.Path)
+			assert.Equal(t, r.Method, http.MethodPut)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t,
+				`{"storeId":"merchant_id","shifts":[{"dayOfWeek":"FRIDAY","start":"18:00:00","duration":300}]}`,
+				string(body))
+			w.WriteHeader(http.StatusCreated)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	merchantService := New(adapter, &am)
+	err := merchantService.UpdateOpeningHours("merchant_id", WeeklySchedule{
+		time.Friday: {mustInterval(t, "18:00", "23:00")},
+	})
+	assert.Nil(t, err)
+}
+
+func TestUpdateOpeningHours_Invalid(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	merchantService := New(adapter, &am)
+	err := merchantService.UpdateOpeningHours("merchant_id", WeeklySchedule{
+		time.Friday: {mustInterval(t, "18:00", "23:00"), mustInterval(t, "22:00", "23:30")},
+	})
+	assert.True(t, errors.Is(err, ErrOverlappingIntervals))
+	assert.Equal(t, ErrMerchantNotSpecified, merchantService.UpdateOpeningHours("", WeeklySchedule{}))
+}
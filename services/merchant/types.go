@@ -11,14 +11,62 @@ type (
 
 	// Address in a merchant
 	Address struct {
-		Formattedaddress string `json:"formattedAddress"`
-		Country          string `json:"country"`
-		State            string `json:"state"`
-		City             string `json:"city"`
-		Neighborhood     string `json:"neighborhood"`
-		Streetname       string `json:"streetName"`
-		Streetnumber     string `json:"streetNumber"`
-		Postalcode       string `json:"postalCode"`
+		Formattedaddress string  `json:"formattedAddress"`
+		Country          string  `json:"country"`
+		State            string  `json:"state"`
+		City             string  `json:"city"`
+		Neighborhood     string  `json:"neighborhood"`
+		Streetname       string  `json:"streetName"`
+		Streetnumber     string  `json:"streetNumber"`
+		Postalcode       string  `json:"postalCode"`
+		Latitude         float64 `json:"latitude,omitempty"`
+		Longitude        float64 `json:"longitude,omitempty"`
+	}
+
+	// MerchantDetails full merchant registration
+	MerchantDetails struct {
+		ID            string      `json:"id"`
+		Name          string      `json:"name"`
+		CorporateName string      `json:"corporateName"`
+		Description   string      `json:"description"`
+		AverageTicket float64     `json:"averageTicket"`
+		Exclusive     bool        `json:"exclusive"`
+		Type          string      `json:"type"`
+		Status        string      `json:"status"`
+		CreatedAt     string      `json:"createdAt"`
+		Phones        []string    `json:"phones,omitempty"`
+		Address       Address     `json:"address"`
+		Operations    []Operation `json:"operations"`
+		Logistics     []Logistic  `json:"logistics,omitempty"`
+	}
+
+	// Operation kind of service the merchant provides (DELIVERY, TAKEOUT, INDOOR)
+	Operation struct {
+		Name          string         `json:"name"`
+		SalesChannels []SalesChannel `json:"salesChannels"`
+	}
+
+	// SalesChannel where the merchant sells an operation (IFOOD_APP, ...)
+	SalesChannel struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	}
+
+	// Logistic delivery provider of the merchant
+	Logistic struct {
+		Name        string `json:"name"`
+		DeliveredBy string `json:"deliveredBy"`
+		Enabled     bool   `json:"enabled"`
+	}
+
+	// OperationStatus availability of one operation and sales channel
+	OperationStatus struct {
+		Operation    string       `json:"operation"`
+		SalesChannel string       `json:"salesChannel"`
+		Available    bool         `json:"available"`
+		State        string       `json:"state"`
+		Validations  []Validation `json:"validations"`
+		Message      Message      `json:"message"`
 	}
 
 	// Unavailabilities group of Unavailability
@@ -39,6 +87,12 @@ type (
 		Minutes     int32  `json:"minutes"`
 	}
 
+	scheduledUnavailability struct {
+		Description string `json:"description"`
+		Start       string `json:"start"`
+		End         string `json:"end"`
+	}
+
 	// UnavailabilityResponse API response
 	UnavailabilityResponse struct {
 		ID          string `json:"id"`
@@ -52,6 +106,22 @@ type (
 	// AvailabilityResponse group of Availability
 	AvailabilityResponse []Availability
 
+	// Validation one of the checks behind a merchant availability
+	Validation struct {
+		ID      string  `json:"id"`
+		Code    string  `json:"code"`
+		State   string  `json:"state"`
+		Message Message `json:"message"`
+	}
+
+	// Message shown to the merchant about its availability
+	Message struct {
+		Title       string `json:"title"`
+		Subtitle    string `json:"subtitle"`
+		Description string `json:"description"`
+		Priority    int    `json:"priority"`
+	}
+
 	// Availability struct to API validate
 	Availability struct {
 		Context    string `json:"context"`
@@ -62,22 +132,7 @@ type (
 			// Type       interface{} `json:"type"`
 			Reopenable bool `json:"reopenable"`
 		} `json:"reopenable"`
-		Validations []struct {
-			ID      string `json:"id"`
-			Code    string `json:"code"`
-			State   string `json:"state"`
-			Message struct {
-				Title       string `json:"title"`
-				Subtitle    string `json:"subtitle"`
-				Description string `json:"description"`
-				Priority    int    `json:"priority"`
-			} `json:"message"`
-		} `json:"validations"`
-		Message struct {
-			Title       string `json:"title"`
-			Subtitle    string `json:"subtitle"`
-			Description string `json:"description"`
-			Priority    int    `json:"priority"`
-		} `json:"message"`
+		Validations []Validation `json:"validations"`
+		Message     Message      `json:"message"`
 	}
 )
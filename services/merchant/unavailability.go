@@ -0,0 +1,74 @@
+package merchant
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kpango/glg"
+)
+
+type (
+	// PlannedUnavailability pause to be created with CreateUnavailability
+	PlannedUnavailability struct {
+		Description string
+		Start       time.Time
+		End         time.Time
+	}
+
+	// UnavailabilityResult outcome of a bulk creation for one merchant and pause
+	UnavailabilityResult struct {
+		MerchantID     string
+		Planned        PlannedUnavailability
+		Unavailability UnavailabilityResponse
+		Err            error
+	}
+)
+
+// CreateUnavailabilities creates every planned pause on every merchant,
+// failures do not stop the remaining creations
+func CreateUnavailabilities(service Service, merchantUUIDs []string, planned ...PlannedUnavailability) (results []UnavailabilityResult) {
+	for _, merchantUUID := range merchantUUIDs {
+		for _, p := range planned {
+			ur, err := service.CreateUnavailability(merchantUUID, p.Description, p.Start, p.End)
+			if err != nil {
+				glg.Error("[SDK] Merchant CreateUnavailabilities merchant: ", merchantUUID, " err: ", err.Error())
+			}
+			results = append(results, UnavailabilityResult{
+				MerchantID:     merchantUUID,
+				Planned:        p,
+				Unavailability: ur,
+				Err:            err,
+			})
+		}
+	}
+	return
+}
+
+// HolidayCalendar returns whole day pauses for the dates ("2006-01-02")
+// in the merchant location, consecutive dates become a single pause
+func HolidayCalendar(dates []string, description string, loc *time.Location) (planned []PlannedUnavailability, err error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	days := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		d, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("holiday '%s' should be formatted as 2006-01-02", date)
+		}
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	for _, d := range days {
+		next := d.AddDate(0, 0, 1)
+		if n := len(planned); n > 0 && !d.After(planned[n-1].End) {
+			if next.After(planned[n-1].End) {
+				planned[n-1].End = next
+			}
+			continue
+		}
+		planned = append(planned, PlannedUnavailability{Description: description, Start: d, End: next})
+	}
+	return
+}
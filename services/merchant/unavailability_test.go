@@ -0,0 +1,97 @@
+package merchant
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUnavailability_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1.0/merchants/merchant_id/unavailabilities", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodPost)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t,
+				`{"description":"Natal","start":"2021-12-25T00:00:00Z","end":"2021-12-26T00:00:00Z"}`,
+				string(body))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(unavNowResponse))
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	merchantService := New(adapter, &am)
+	start := time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC)
+	ur, err := merchantService.CreateUnavailability("merchant_id", "Natal", start, start.AddDate(0, 0, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, "d0fd503f-7a2f-4bbb-8a5b-cee335ee4233", ur.ID)
+}
+
+func TestCreateUnavailability_Invalid(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	merchantService := New(adapter, &am)
+	start := time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC)
+	_, err := merchantService.CreateUnavailability("", "Natal", start, start.Add(time.Hour))
+	assert.Equal(t, ErrMerchantNotSpecified, err)
+	_, err = merchantService.CreateUnavailability("merchant_id", "Natal", start, start)
+	assert.Equal(t, ErrInvalidUnavailabilityPeriod, err)
+}
+
+func TestCreateUnavailabilities(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			if strings.Contains(r.URL.Path, "closed") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(unavNowResponse))
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	planned, err := HolidayCalendar([]string{"2021-12-25", "2022-01-01"}, "Feriado", time.UTC)
+	assert.Nil(t, err)
+	results := CreateUnavailabilities(New(adapter, &am), []string{"a", "closed"}, planned...)
+	assert.Equal(t, 4, len(results))
+	assert.Equal(t, 4, len(paths))
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, "a", results[1].MerchantID)
+	assert.Equal(t, planned[1], results[1].Planned)
+	assert.NotNil(t, results[2].Err)
+	assert.Equal(t, "closed", results[3].MerchantID)
+}
+
+func TestHolidayCalendar(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*60*60)
+	planned, err := HolidayCalendar(
+		[]string{"2021-12-31", "2021-12-25", "2022-01-01", "2021-12-31", "2021-12-24"}, "Feriado", loc)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(planned))
+	assert.Equal(t, time.Date(2021, 12, 24, 0, 0, 0, 0, loc), planned[0].Start)
+	assert.Equal(t, time.Date(2021, 12, 26, 0, 0, 0, 0, loc), planned[0].End)
+	assert.Equal(t, time.Date(2021, 12, 31, 0, 0, 0, 0, loc), planned[1].Start)
+	assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, loc), planned[1].End)
+	assert.Equal(t, "Feriado", planned[1].Description)
+
+	_, err = HolidayCalendar([]string{"25/12/2021"}, "Natal", loc)
+	assert.NotNil(t, err)
+}
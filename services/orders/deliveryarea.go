@@ -0,0 +1,91 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/merchant"
+	"github.com/kpango/glg"
+)
+
+var (
+	// ErrInsideDeliveryArea the address is inside the merchant delivery area
+	ErrInsideDeliveryArea = errors.New("Order address is inside the delivery area")
+
+	// OutOfAreaCancelCodes cancel codes for orders out of the delivery area
+	OutOfAreaCancelCodes = []string{"506", "807"}
+)
+
+// DeliveryAreaCheck where the order address is related to the merchant delivery areas
+type DeliveryAreaCheck struct {
+	merchant.AreaMatch
+	Reference string
+}
+
+// Coordinate parses the delivery address coordinates
+func (od OrderDetails) Coordinate() (c merchant.Coordinate, err error) {
+	coordinates := od.Deliveryaddress.Coordinates
+	lat, err := strconv.ParseFloat(strings.TrimSpace(coordinates.Latitude), 64)
+	if err != nil {
+		err = fmt.Errorf("Order '%s' latitude '%s' is invalid", od.ID, coordinates.Latitude)
+		return
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(coordinates.Longitude), 64)
+	if err != nil {
+		err = fmt.Errorf("Order '%s' longitude '%s' is invalid", od.ID, coordinates.Longitude)
+		return
+	}
+	return merchant.Coordinate{Latitude: lat, Longitude: lon}, nil
+}
+
+// CheckDeliveryArea locates the order address among the merchant delivery areas
+func CheckDeliveryArea(od OrderDetails, areas []merchant.DeliveryArea) (check DeliveryAreaCheck, err error) {
+	point, err := od.Coordinate()
+	if err != nil {
+		glg.Error("[SDK] Orders CheckDeliveryArea: ", err.Error())
+		return
+	}
+	match, err := merchant.Locate(areas, point)
+	if err != nil {
+		glg.Error("[SDK] Orders CheckDeliveryArea merchant.Locate: ", err.Error())
+		return
+	}
+	return DeliveryAreaCheck{AreaMatch: match, Reference: od.Reference}, nil
+}
+
+// Warning describes an address out of the delivery area for operators,
+// empty when the address is inside
+func (c DeliveryAreaCheck) Warning() string {
+	if c.Inside {
+		return ""
+	}
+	return fmt.Sprintf("Order '%s' address is %.0fm outside the delivery area '%s'",
+		c.Reference, c.DistanceInMeters, c.Area.Name)
+}
+
+// CancelOutOfArea cancels the order with one of OutOfAreaCancelCodes
+// only when its address is out of every delivery area
+func CancelOutOfArea(service Service, od OrderDetails, areas []merchant.DeliveryArea, code string) (check DeliveryAreaCheck, err error) {
+	valid := false
+	for _, c := range OutOfAreaCancelCodes {
+		valid = valid || (c == code)
+	}
+	if !valid {
+		err = fmt.Errorf("cancel code '%s' is not an out of delivery area code", code)
+		glg.Error("[SDK] Orders CancelOutOfArea: ", err.Error())
+		return
+	}
+	if check, err = CheckDeliveryArea(od, areas); err != nil {
+		return
+	}
+	if check.Inside {
+		err = ErrInsideDeliveryArea
+		glg.Warn("[SDK] Orders CancelOutOfArea: ", err.Error(), " orderReference: ", od.Reference)
+		return
+	}
+	glg.Info("[SDK] Orders CancelOutOfArea: ", check.Warning())
+	err = service.SetCancelStatus(od.Reference, code)
+	return
+}
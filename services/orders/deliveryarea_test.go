@@ -0,0 +1,75 @@
+package orders
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/merchant"
+	"github.com/stretchr/testify/assert"
+)
+
+var areas = []merchant.DeliveryArea{{
+	ID: "square", Name: "Centro", Type: merchant.AreaPolygon, Polygon: []merchant.Coordinate{
+		{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 0.01},
+		{Latitude: 0.01, Longitude: 0.01}, {Latitude: 0.01, Longitude: 0},
+	},
+}}
+
+func orderAt(lat, lon string) OrderDetails {
+	return OrderDetails{ID: "order_id", Reference: "reference_id", Deliveryaddress: DeliveryAddress{
+		Coordinates: Coordinates{Latitude: lat, Longitude: lon},
+	}}
+}
+
+func TestCheckDeliveryArea(t *testing.T) {
+	check, err := CheckDeliveryArea(orderAt("0.005", "0.005"), areas)
+	assert.Nil(t, err)
+	assert.True(t, check.Inside)
+	assert.Equal(t, "", check.Warning())
+
+	check, err = CheckDeliveryArea(orderAt("0.005", " 0.02"), areas)
+	assert.Nil(t, err)
+	assert.False(t, check.Inside)
+	assert.Equal(t, "Order 'reference_id' address is 1112m outside the delivery area 'Centro'", check.Warning())
+
+	_, err = CheckDeliveryArea(orderAt("", "0"), areas)
+	assert.NotNil(t, err)
+	_, err = CheckDeliveryArea(orderAt("0", "x"), areas)
+	assert.NotNil(t, err)
+	_, err = CheckDeliveryArea(orderAt("0", "0"), nil)
+	assert.Equal(t, merchant.ErrNoDeliveryAreas, err)
+}
+
+func TestCancelOutOfArea(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			assert.Equal(t, "/v3.0/orders/reference_id/statuses/cancellationRequested", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"cancellationCode":"506","details":"PEDIDO FORA DA ÁREA DE ENTREGA"}`, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+
+	_, err := CancelOutOfArea(ordersService, orderAt("0.005", "0.005"), areas, "506")
+	assert.Equal(t, ErrInsideDeliveryArea, err)
+	_, err = CancelOutOfArea(ordersService, orderAt("0.005", "0.02"), areas, "501")
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, calls)
+
+	check, err := CancelOutOfArea(ordersService, orderAt("0.005", "0.02"), areas, "506")
+	assert.Nil(t, err)
+	assert.False(t, check.Inside)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,129 @@
+package orders
+
+import (
+	"sync"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/kpango/glg"
+)
+
+const (
+	// DriverRequested the driver request was received by iFood
+	DriverRequested DriverRequestStatus = "REQUESTED"
+	// DriverRequestSucceeded iFood accepted the request and is looking for a driver
+	DriverRequestSucceeded DriverRequestStatus = "SUCCEEDED"
+	// DriverRequestFailed iFood could not fulfill the request
+	DriverRequestFailed DriverRequestStatus = "FAILED"
+	// DriverAssigned a driver was assigned to the order
+	DriverAssigned DriverRequestStatus = "ASSIGNED"
+)
+
+var driverEventStatus = map[string]DriverRequestStatus{
+	"REQUEST_DRIVER":         DriverRequested,
+	"REQUEST_DRIVER_SUCCESS": DriverRequestSucceeded,
+	"REQUEST_DRIVER_FAILED":  DriverRequestFailed,
+	"ASSIGN_DRIVER":          DriverAssigned,
+}
+
+type (
+	// DriverRequestStatus progress of a driver request
+	DriverRequestStatus string
+
+	// DriverRequestOutcome is handed to the callback on each driver event
+	DriverRequestOutcome struct {
+		OrderID   string
+		Reference string
+		QuoteID   string
+		Status    DriverRequestStatus
+		Event     events.Event
+	}
+
+	// DriverRequests requests iFood drivers and follows their
+	// outcome through the polled events
+	DriverRequests struct {
+		service Service
+		mu      sync.Mutex
+		pending map[string]driverRequestCallback
+	}
+
+	driverRequestCallback struct {
+		reference string
+		quoteID   string
+		callback  func(DriverRequestOutcome)
+	}
+)
+
+// Done reports if no more events are expected for the request
+func (s DriverRequestStatus) Done() bool {
+	return (s == DriverRequestFailed) || (s == DriverAssigned)
+}
+
+// NewDriverRequests returns a driver request tracker
+func NewDriverRequests(service Service) *DriverRequests {
+	return &DriverRequests{service: service, pending: make(map[string]driverRequestCallback)}
+}
+
+// Request asks for a driver with a quote from DriverAvailability,
+// callback is called on each driver event of the order until it is done
+//
+// the request is sent with the order reference and followed by the
+// order id, the correlation id of its events
+func (d *DriverRequests) Request(od OrderDetails, quoteID string, callback func(DriverRequestOutcome)) (err error) {
+	if od.ID == "" {
+		err = ErrOrderIDNotSpecified
+		glg.Error("[SDK] Orders DriverRequests Request: ", err.Error())
+		return
+	}
+	if err = d.service.RequestDriver(od.Reference, quoteID); err != nil {
+		glg.Error("[SDK] Orders DriverRequests RequestDriver: ", err.Error())
+		return
+	}
+	d.mu.Lock()
+	d.pending[od.ID] = driverRequestCallback{od.Reference, quoteID, callback}
+	d.mu.Unlock()
+	return
+}
+
+// Pending returns the ids of the orders still waiting for a driver
+func (d *DriverRequests) Pending() (orderIDs []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for orderID := range d.pending {
+		orderIDs = append(orderIDs, orderID)
+	}
+	return
+}
+
+// HandleEvent reports if the event was a driver event of a requested order
+func (d *DriverRequests) HandleEvent(event events.Event) bool {
+	name := event.Name()
+	if name == "CANCELLED" {
+		d.mu.Lock()
+		delete(d.pending, event.CorrelationID)
+		d.mu.Unlock()
+		return false
+	}
+	status, ok := driverEventStatus[name]
+	if !ok {
+		return false
+	}
+	d.mu.Lock()
+	pending, ok := d.pending[event.CorrelationID]
+	if ok && status.Done() {
+		delete(d.pending, event.CorrelationID)
+	}
+	d.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if pending.callback != nil {
+		pending.callback(DriverRequestOutcome{
+			OrderID:   event.CorrelationID,
+			Reference: pending.reference,
+			QuoteID:   pending.quoteID,
+			Status:    status,
+			Event:     event,
+		})
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/mocks"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const driverAvailability = `{
+	"id": "quote_id",
+	"expirationAt": "2021-03-01T19:40:00Z",
+	"createdAt": "2021-03-01T19:30:00Z",
+	"distance": 2300,
+	"preparationTime": 900,
+	"quote": {
+		"grossValue": 9.9,
+		"discount": 1.9,
+		"raise": 0,
+		"netValue": 8
+	},
+	"deliveryTime": {"min": 1200, "max": 1800},
+	"hasPaymentMethods": true
+}`
+
+func TestDriverAvailability_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2.0/orders/reference_id/delivery-availabilities", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, driverAvailability)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	da, err := ordersService.DriverAvailability("reference_id")
+	assert.Nil(t, err)
+	assert.Equal(t, "quote_id", da.ID)
+	assert.Equal(t, float64(8), da.Quote.NetValue)
+	assert.Equal(t, 1800, da.DeliveryTime.Max)
+}
+
+func TestDriverAvailability_NoRefereceID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	ordersService := New(adapter, &am)
+	_, err := ordersService.DriverAvailability("")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
+
+func TestDriverAvailability_StatusBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":{"code":"OutOfCoverageArea","message":"out of area"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	_, err := ordersService.DriverAvailability("reference_id")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "OutOfCoverageArea")
+}
+
+func TestDriverAvailability_DoReqErr(t *testing.T) {
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	httpmock := &mocks.HttpClientMock{}
+	httpmock.On("Do", mock.Anything).Once().Return(nil, errors.New("some err"))
+	adapter := httpadapter.New(httpmock, "")
+	ordersService := New(adapter, &am)
+	_, err := ordersService.DriverAvailability("reference_id")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "some")
+}
+
+func TestRequestDriver_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2.0/orders/reference_id/request-driver", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodPost)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"quoteId":"quote_id"}`, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.RequestDriver("reference_id", "quote_id")
+	assert.Nil(t, err)
+}
+
+func TestRequestDriver_NoQuote(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	ordersService := New(adapter, &am)
+	err := ordersService.RequestDriver("reference_id", "")
+	assert.Equal(t, ErrQuoteNotSpecified, err)
+}
+
+func TestRequestDriver_StatusBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":{"code":"QuoteExpired"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.RequestDriver("reference_id", "quote_id")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "QuoteExpired")
+}
+
+func TestDriverRequests_Events(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	requests := NewDriverRequests(New(adapter, &am))
+	var outcomes []DriverRequestOutcome
+	callback := func(o DriverRequestOutcome) { outcomes = append(outcomes, o) }
+	assert.Nil(t, requests.Request(OrderDetails{ID: "order_a", Reference: "ref_a"}, "quote_a", callback))
+	assert.Nil(t, requests.Request(OrderDetails{ID: "order_b", Reference: "ref_b"}, "quote_b", callback))
+	assert.Equal(t, 2, len(paths))
+	assert.Contains(t, paths[0], "ref_a")
+	// events carry the order id, not the reference
+	assert.False(t, requests.HandleEvent(events.Event{Code: "RDS", CorrelationID: "ref_a"}))
+	assert.False(t, requests.HandleEvent(events.Event{Code: "PLACED", CorrelationID: "order_a"}))
+	assert.False(t, requests.HandleEvent(events.Event{Code: "RDS", CorrelationID: "order_c"}))
+	assert.True(t, requests.HandleEvent(events.Event{Code: "RDS", CorrelationID: "order_a"}))
+	assert.True(t, requests.HandleEvent(events.Event{Code: "ASSIGN_DRIVER", CorrelationID: "order_a"}))
+	assert.False(t, requests.HandleEvent(events.Event{Code: "ASSIGN_DRIVER", CorrelationID: "order_a"}))
+	assert.True(t, requests.HandleEvent(events.Event{Code: "REQUEST_DRIVER_FAILED", CorrelationID: "order_b"}))
+	assert.Equal(t, 3, len(outcomes))
+	assert.Equal(t, DriverRequestSucceeded, outcomes[0].Status)
+	assert.Equal(t, "order_a", outcomes[0].OrderID)
+	assert.Equal(t, "ref_a", outcomes[0].Reference)
+	assert.Equal(t, DriverAssigned, outcomes[1].Status)
+	assert.Equal(t, "quote_b", outcomes[2].QuoteID)
+	assert.Equal(t, DriverRequestFailed, outcomes[2].Status)
+	assert.Equal(t, 0, len(requests.Pending()))
+}
+
+func TestDriverRequests_RequestErr(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	requests := NewDriverRequests(New(adapter, &am))
+	err := requests.Request(OrderDetails{ID: "order_a", Reference: "ref_a"}, "", nil)
+	assert.Equal(t, ErrQuoteNotSpecified, err)
+	err = requests.Request(OrderDetails{Reference: "ref_a"}, "quote_a", nil)
+	assert.Equal(t, ErrOrderIDNotSpecified, err)
+	assert.Equal(t, 0, len(requests.Pending()))
+}
@@ -5,6 +5,12 @@ import "errors"
 var (
 	// ErrOrderReferenceNotSpecified no order_id specified
 	ErrOrderReferenceNotSpecified = errors.New("Order reference not specified")
+	// ErrOrderIDNotSpecified order without id
+	ErrOrderIDNotSpecified = errors.New("Order ID not specified")
 	// ErrCancelCodeNotSpecified no cancel code provided
 	ErrCancelCodeNotSpecified = errors.New("Order cancel code not specified")
+	// ErrQuoteNotSpecified no driver quote id provided
+	ErrQuoteNotSpecified = errors.New("Driver quote ID not specified")
+	// ErrInvalidIncrement preparation time or delay out of the allowed increments
+	ErrInvalidIncrement = errors.New("Time increment should be a positive multiple of 5 minutes within the allowed maximum")
 )
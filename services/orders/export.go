@@ -0,0 +1,319 @@
+package orders
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kpango/glg"
+)
+
+const (
+	// RowPerItem exports one row for each item of an order
+	RowPerItem ExportMode = iota
+	// RowPerPayment exports one row for each payment of an order
+	RowPerPayment
+)
+
+const (
+	// LocaleEnUS formats numbers as 1234.50
+	LocaleEnUS = "en-US"
+	// LocalePtBR formats numbers as 1234,50
+	LocalePtBR = "pt-BR"
+)
+
+var (
+	// DefaultItemColumns exported when RowPerItem is used without columns
+	DefaultItemColumns = []string{
+		"order_id", "short_reference", "created_at", "merchant_id", "customer_name",
+		"customer_tax_id", "item_name", "item_external_code", "item_quantity",
+		"item_price", "item_discount", "item_addition", "item_total_price",
+	}
+	// DefaultPaymentColumns exported when RowPerPayment is used without columns
+	DefaultPaymentColumns = []string{
+		"order_id", "short_reference", "created_at", "merchant_id", "customer_name",
+		"customer_tax_id", "subtotal", "delivery_fee", "total_price", "payment_name",
+		"payment_code", "payment_value", "payment_prepaid",
+	}
+	// RevenuePaymentColumns payment columns with the fees, benefits and net revenue,
+	// set as ExportConfig.Columns to opt in
+	RevenuePaymentColumns = []string{
+		"order_id", "short_reference", "created_at", "merchant_id", "customer_name",
+		"customer_tax_id", "subtotal", "delivery_fee", "additional_fees", "benefits_ifood",
+		"benefits_merchant", "net_revenue", "total_price", "payment_name", "payment_code",
+		"payment_value", "payment_prepaid",
+	}
+
+	exportColumns = map[string]exportColumn{
+		"order_id":            {value: func(r exportRow) string { return r.order.ID }},
+		"reference":           {value: func(r exportRow) string { return r.order.Reference }},
+		"short_reference":     {value: func(r exportRow) string { return r.order.Shortreference }},
+		"created_at":          {value: func(r exportRow) string { return r.order.Createdat }},
+		"type":                {value: func(r exportRow) string { return string(r.order.Type) }},
+		"merchant_id":         {value: func(r exportRow) string { return r.order.Merchant.ID }},
+		"merchant_name":       {value: func(r exportRow) string { return r.order.Merchant.Name }},
+		"customer_id":         {value: func(r exportRow) string { return r.order.Customer.ID }},
+		"customer_name":       {value: func(r exportRow) string { return r.order.Customer.Name }},
+		"customer_tax_id":     {value: func(r exportRow) string { return r.order.Customer.Taxpayeridentificationnumber }},
+		"subtotal":            {numeric: true, decimals: 2, value: func(r exportRow) string { return r.order.Subtotal }},
+		"delivery_fee":        {numeric: true, decimals: 2, value: func(r exportRow) string { return r.order.Deliveryfee }},
+		"total_price":         {numeric: true, decimals: 2, value: func(r exportRow) string { return r.order.Totalprice }},
+		"additional_fees":     {numeric: true, decimals: 2, value: revenueValue(func(r Revenue) float64 { return r.AdditionalFees })},
+		"benefits_ifood":      {numeric: true, decimals: 2, value: revenueValue(func(r Revenue) float64 { return r.IfoodBenefits })},
+		"benefits_merchant":   {numeric: true, decimals: 2, value: revenueValue(func(r Revenue) float64 { return r.MerchantBenefits })},
+		"net_revenue":         {numeric: true, decimals: 2, value: revenueValue(func(r Revenue) float64 { return r.Net })},
+		"item_name":           {value: func(r exportRow) string { return r.item.Name }},
+		"item_external_code":  {value: func(r exportRow) string { return r.item.Externalcode }},
+		"item_quantity":       {numeric: true, decimals: -1, value: func(r exportRow) string { return r.item.Quantity }},
+		"item_price":          {numeric: true, decimals: 2, value: func(r exportRow) string { return r.item.Price }},
+		"item_subitems_price": {numeric: true, decimals: 2, value: func(r exportRow) string { return r.item.Subitemsprice }},
+		"item_discount":       {numeric: true, decimals: 2, value: func(r exportRow) string { return r.item.Discount }},
+		"item_addition":       {numeric: true, decimals: 2, value: func(r exportRow) string { return r.item.Addition }},
+		"item_total_price":    {numeric: true, decimals: 2, value: func(r exportRow) string { return r.item.Totalprice }},
+		"item_observations":   {value: func(r exportRow) string { return r.item.Observations }},
+		"payment_name":        {value: func(r exportRow) string { return r.payment.Name }},
+		"payment_code":        {value: func(r exportRow) string { return r.payment.Code }},
+		"payment_value":       {numeric: true, decimals: 2, value: func(r exportRow) string { return r.payment.Value }},
+		"payment_prepaid":     {value: func(r exportRow) string { return r.payment.Prepaid }},
+		"payment_issuer":      {value: func(r exportRow) string { return r.payment.Issuer }},
+		"payment_collector":   {value: func(r exportRow) string { return r.payment.Collector }},
+		"payment_change_for":  {numeric: true, decimals: 2, value: func(r exportRow) string { return r.payment.ChangeFor }},
+	}
+)
+
+type (
+	// ExportMode determinates how orders are flattened into rows
+	ExportMode int
+
+	// ExportConfig configures an order exporter
+	//
+	// Columns defaults to DefaultItemColumns or DefaultPaymentColumns,
+	// Locale to LocaleEnUS. CSV files use ';' as separator on LocalePtBR
+	// unless Comma is set, and text starting with '=', '+', '-' or '@' is
+	// prefixed with "'" so spreadsheets do not run it as a formula. JSON
+	// Lines always write numbers as JSON numbers.
+	ExportConfig struct {
+		Mode    ExportMode
+		Columns []string
+		Locale  string
+		Comma   rune
+	}
+
+	// Exporter writes orders as they arrive, call Flush when done
+	Exporter interface {
+		Write(od OrderDetails) error
+		Flush() error
+	}
+
+	csvExporter struct {
+		cfg           ExportConfig
+		writer        *csv.Writer
+		headerWritten bool
+	}
+
+	jsonLinesExporter struct {
+		cfg    ExportConfig
+		writer *bufio.Writer
+	}
+
+	exportColumn struct {
+		numeric  bool
+		decimals int
+		value    func(r exportRow) string
+	}
+
+	exportRow struct {
+		order   *OrderDetails
+		item    *Item
+		payment *Payment
+	}
+)
+
+// NewCSVExporter returns an exporter that writes a CSV with a header line
+func NewCSVExporter(w io.Writer, cfg ExportConfig) (Exporter, error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	if cfg.Comma != 0 {
+		writer.Comma = cfg.Comma
+	} else if cfg.Locale == LocalePtBR {
+		writer.Comma = ';'
+	}
+	return &csvExporter{cfg: cfg, writer: writer}, nil
+}
+
+// NewJSONLinesExporter returns an exporter that writes a JSON object per row
+func NewJSONLinesExporter(w io.Writer, cfg ExportConfig) (Exporter, error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return &jsonLinesExporter{cfg: cfg, writer: bufio.NewWriter(w)}, nil
+}
+
+// ExportOrders fetches the details of each order reference and writes them
+func ExportOrders(service Service, exporter Exporter, references ...string) (err error) {
+	var od OrderDetails
+	for _, reference := range references {
+		if od, err = service.GetDetails(reference); err != nil {
+			glg.Error("[SDK] Orders ExportOrders GetDetails: ", err.Error())
+			return
+		}
+		if err = exporter.Write(od); err != nil {
+			glg.Error("[SDK] Orders ExportOrders Write: ", err.Error())
+			return
+		}
+	}
+	return exporter.Flush()
+}
+
+func (c ExportConfig) normalize() (ExportConfig, error) {
+	if (c.Mode != RowPerItem) && (c.Mode != RowPerPayment) {
+		return c, fmt.Errorf("export mode '%d' is invalid", c.Mode)
+	}
+	if c.Locale == "" {
+		c.Locale = LocaleEnUS
+	}
+	if (c.Locale != LocaleEnUS) && (c.Locale != LocalePtBR) {
+		return c, fmt.Errorf("export locale '%s' should be '%s' or '%s'", c.Locale, LocaleEnUS, LocalePtBR)
+	}
+	if len(c.Columns) == 0 {
+		c.Columns = DefaultItemColumns
+		if c.Mode == RowPerPayment {
+			c.Columns = DefaultPaymentColumns
+		}
+	}
+	for _, column := range c.Columns {
+		if _, ok := exportColumns[column]; !ok {
+			return c, fmt.Errorf("export column '%s' does not exist", column)
+		}
+	}
+	return c, nil
+}
+
+func (c ExportConfig) rows(od *OrderDetails) (rows []exportRow) {
+	switch c.Mode {
+	case RowPerPayment:
+		for i := range od.Payments {
+			rows = append(rows, exportRow{order: od, item: &Item{}, payment: &od.Payments[i]})
+		}
+	default:
+		for i := range od.Items {
+			rows = append(rows, exportRow{order: od, item: &od.Items[i], payment: &Payment{}})
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, exportRow{order: od, item: &Item{}, payment: &Payment{}})
+	}
+	return
+}
+
+func (e *csvExporter) Write(od OrderDetails) (err error) {
+	if !e.headerWritten {
+		if err = e.writer.Write(e.cfg.Columns); err != nil {
+			return
+		}
+		e.headerWritten = true
+	}
+	for _, r := range e.cfg.rows(&od) {
+		record := make([]string, len(e.cfg.Columns))
+		for i, name := range e.cfg.Columns {
+			column := exportColumns[name]
+			record[i] = column.value(r)
+			if column.numeric {
+				record[i] = formatNumber(record[i], e.cfg.Locale, column.decimals)
+			} else {
+				record[i] = escapeFormula(record[i])
+			}
+		}
+		if err = e.writer.Write(record); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (e *csvExporter) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *jsonLinesExporter) Write(od OrderDetails) (err error) {
+	for _, r := range e.cfg.rows(&od) {
+		e.writer.WriteByte('{')
+		for i, name := range e.cfg.Columns {
+			if i > 0 {
+				e.writer.WriteByte(',')
+			}
+			column := exportColumns[name]
+			key, _ := json.Marshal(name)
+			e.writer.Write(key)
+			e.writer.WriteByte(':')
+			e.writer.Write(jsonValue(column.value(r), column.numeric))
+		}
+		if _, err = e.writer.WriteString("}\n"); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (e *jsonLinesExporter) Flush() error {
+	return e.writer.Flush()
+}
+
+// revenueValue exports a Revenue field, empty when the order amounts are invalid
+func revenueValue(field func(r Revenue) float64) func(r exportRow) string {
+	return func(r exportRow) string {
+		revenue, err := r.order.Revenue()
+		if err != nil {
+			return ""
+		}
+		return strconv.FormatFloat(field(revenue), 'f', -1, 64)
+	}
+}
+
+// jsonValue writes numeric columns as JSON numbers, values that are not
+// finite numbers, as "NaN" or "Inf", are kept as strings
+func jsonValue(value string, numeric bool) []byte {
+	if numeric {
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if (err == nil) && !math.IsNaN(n) && !math.IsInf(n, 0) {
+			return []byte(strconv.FormatFloat(n, 'f', -1, 64))
+		}
+		if value == "" {
+			return []byte("null")
+		}
+	}
+	b, _ := json.Marshal(value)
+	return b
+}
+
+// formatNumber formats numeric API strings for the locale,
+// values that are not numbers are kept as text
+func formatNumber(value, locale string, decimals int) string {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return escapeFormula(value)
+	}
+	formatted := strconv.FormatFloat(n, 'f', decimals, 64)
+	if locale == LocalePtBR {
+		formatted = strings.Replace(formatted, ".", ",", 1)
+	}
+	return formatted
+}
+
+// escapeFormula prefixes text a spreadsheet would read as a formula with "'"
+func escapeFormula(value string) string {
+	if (value != "") && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
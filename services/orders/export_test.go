@@ -0,0 +1,180 @@
+package orders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+var exportOrder = OrderDetails{
+	ID:             "order-1",
+	Shortreference: "7788",
+	Createdat:      "2021-03-01T19:30:00Z",
+	Customer:       Customer{Name: "João, o cliente", Taxpayeridentificationnumber: "12345678900"},
+	Items: []Item{
+		{Name: "Pizza", Quantity: "1", Price: "1234.5", Totalprice: "1234.5"},
+		{Name: "Refrigerante", Quantity: "2", Price: "5", Totalprice: "10"},
+	},
+	Payments: []Payment{
+		{Name: "CRÉDITO", Code: "CRE", Value: "1244.5", Prepaid: "true"},
+	},
+	Benefits: []Benefit{
+		{Value: "10", Target: BenefitTargetCart, SponsorshipValues: []Sponsorship{
+			{Name: SponsorIfood, Value: "6"},
+			{Name: SponsorMerchant, Value: "4"},
+		}},
+	},
+	AdditionalFees: []AdditionalFee{{Type: "SMALL_ORDER_FEE", Value: "3"}},
+	Subtotal:       "1251.5",
+	Deliveryfee:    "0",
+	Totalprice:     "1244.5",
+}
+
+func TestNewCSVExporter_InvalidConfig(t *testing.T) {
+	_, err := NewCSVExporter(&bytes.Buffer{}, ExportConfig{Mode: ExportMode(5)})
+	assert.NotNil(t, err)
+	_, err = NewCSVExporter(&bytes.Buffer{}, ExportConfig{Locale: "fr-FR"})
+	assert.NotNil(t, err)
+	_, err = NewCSVExporter(&bytes.Buffer{}, ExportConfig{Columns: []string{"nope"}})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "nope")
+}
+
+func TestCSVExporter_RowPerItem(t *testing.T) {
+	buf := &bytes.Buffer{}
+	exp, err := NewCSVExporter(buf, ExportConfig{
+		Columns: []string{"order_id", "customer_name", "item_name", "item_quantity", "item_total_price"},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, exp.Write(exportOrder))
+	assert.Nil(t, exp.Write(exportOrder))
+	assert.Nil(t, exp.Flush())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, "order_id,customer_name,item_name,item_quantity,item_total_price", lines[0])
+	assert.Equal(t, `order-1,"João, o cliente",Pizza,1,1234.50`, lines[1])
+	assert.Equal(t, `order-1,"João, o cliente",Refrigerante,2,10.00`, lines[2])
+}
+
+func TestCSVExporter_RowPerPaymentPtBR(t *testing.T) {
+	buf := &bytes.Buffer{}
+	exp, err := NewCSVExporter(buf, ExportConfig{Mode: RowPerPayment, Locale: LocalePtBR})
+	assert.Nil(t, err)
+	assert.Nil(t, exp.Write(exportOrder))
+	assert.Nil(t, exp.Flush())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, strings.Join(DefaultPaymentColumns, ";"), lines[0])
+	assert.Equal(t,
+		"order-1;7788;2021-03-01T19:30:00Z;;João, o cliente;12345678900;1251,50;0,00;1244,50;CRÉDITO;CRE;1244,50;true",
+		lines[1])
+}
+
+func TestCSVExporter_RevenuePaymentColumns(t *testing.T) {
+	buf := &bytes.Buffer{}
+	exp, err := NewCSVExporter(buf, ExportConfig{Mode: RowPerPayment, Columns: RevenuePaymentColumns, Locale: LocalePtBR})
+	assert.Nil(t, err)
+	assert.Nil(t, exp.Write(exportOrder))
+	assert.Nil(t, exp.Flush())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, strings.Join(RevenuePaymentColumns, ";"), lines[0])
+	assert.Equal(t,
+		"order-1;7788;2021-03-01T19:30:00Z;;João, o cliente;12345678900;1251,50;0,00;3,00;6,00;4,00;1250,50;1244,50;CRÉDITO;CRE;1244,50;true",
+		lines[1])
+}
+
+func TestCSVExporter_OrderWithoutItems(t *testing.T) {
+	buf := &bytes.Buffer{}
+	exp, err := NewCSVExporter(buf, ExportConfig{Columns: []string{"order_id", "item_name"}})
+	assert.Nil(t, err)
+	assert.Nil(t, exp.Write(OrderDetails{ID: "empty"}))
+	assert.Nil(t, exp.Flush())
+	assert.Equal(t, "order_id,item_name\nempty,\n", buf.String())
+}
+
+func TestCSVExporter_Formulas(t *testing.T) {
+	buf := &bytes.Buffer{}
+	exp, err := NewCSVExporter(buf, ExportConfig{
+		Columns: []string{"customer_name", "item_name", "item_observations", "item_quantity", "item_discount"},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, exp.Write(OrderDetails{
+		Customer: Customer{Name: "=HYPERLINK(\"http://x\")"},
+		Items: []Item{
+			{Name: "+Pizza", Observations: "@SUM(A1)", Quantity: "-1", Discount: "=1+1"},
+			{Name: "Pizza", Observations: "sem cebola", Quantity: "1", Discount: "-2.5"},
+		},
+	}))
+	assert.Nil(t, exp.Flush())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, `"'=HYPERLINK(""http://x"")",'+Pizza,'@SUM(A1),-1,'=1+1`, lines[1])
+	assert.Equal(t, `"'=HYPERLINK(""http://x"")",Pizza,sem cebola,1,-2.50`, lines[2])
+}
+
+func TestJSONLinesExporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	exp, err := NewJSONLinesExporter(buf, ExportConfig{
+		Columns: []string{"order_id", "item_name", "item_quantity", "item_total_price", "item_discount"},
+		Locale:  LocalePtBR,
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, exp.Write(exportOrder))
+	assert.Nil(t, exp.Flush())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t,
+		`{"order_id":"order-1","item_name":"Pizza","item_quantity":1,"item_total_price":1234.5,"item_discount":null}`,
+		lines[0])
+	row := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &row))
+	assert.Equal(t, float64(10), row["item_total_price"])
+}
+
+func TestJSONValue(t *testing.T) {
+	assert.Equal(t, "12.5", string(jsonValue(" 12.50", true)))
+	assert.Equal(t, "null", string(jsonValue("", true)))
+	for _, value := range []string{"NaN", "Inf", "-Infinity", "+inf"} {
+		assert.Equal(t, `"`+value+`"`, string(jsonValue(value, true)))
+	}
+	assert.Equal(t, `"12"`, string(jsonValue("12", false)))
+}
+
+func TestExportOrders(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v3.0/orders/reference_id", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, orderDetails)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	buf := &bytes.Buffer{}
+	exp, err := NewCSVExporter(buf, ExportConfig{Columns: []string{"order_id", "item_name"}})
+	assert.Nil(t, err)
+	err = ExportOrders(New(adapter, &am), exp, "reference_id")
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, "REFERENCIA,Nome do item", lines[1])
+}
+
+func TestExportOrders_GetDetailsErr(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	exp, err := NewCSVExporter(&bytes.Buffer{}, ExportConfig{})
+	assert.Nil(t, err)
+	err = ExportOrders(New(adapter, &am), exp, "")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
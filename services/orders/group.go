@@ -0,0 +1,232 @@
+package orders
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/kpango/glg"
+)
+
+var (
+	// groupMetadataKeys are the event metadata keys that may carry the group id
+	groupMetadataKeys = []string{"groupId", "GROUP_ID", "orderGroupId"}
+
+	groupEvents = map[string]bool{
+		"ADDED_TO_GROUP":       true,
+		"ASSIGNED_WITH_GROUP":  true,
+		"COLLECTED_IN_GROUP":   true,
+		"EXECUTED_WITH_GROUP":  true,
+		"CANCELLED_WITH_GROUP": true,
+	}
+)
+
+type (
+	// OrderGroups follows batched deliveries through the group events
+	//
+	// orders are identified by their id, the correlation id of the events,
+	// the status changes are sent with the reference of the tracked orders
+	OrderGroups struct {
+		mu         sync.Mutex
+		groupOf    map[string]string
+		groups     map[string]*OrderGroup
+		references map[string]string
+	}
+
+	// OrderGroup orders delivered together by the same driver
+	//
+	// Status is the name of the last group event received,
+	// Orders the ids of its orders
+	OrderGroup struct {
+		ID     string
+		Status string
+		Orders []string
+	}
+
+	// GroupResult outcome of a group status change for one order
+	GroupResult struct {
+		OrderID   string
+		Reference string
+		Err       error
+	}
+)
+
+// NewOrderGroups returns an empty group tracker
+func NewOrderGroups() *OrderGroups {
+	return &OrderGroups{
+		groupOf:    make(map[string]string),
+		groups:     make(map[string]*OrderGroup),
+		references: make(map[string]string),
+	}
+}
+
+// Track keeps the reference of an order, used by Dispatch and ReadyToDeliver
+func (g *OrderGroups) Track(od OrderDetails) {
+	g.mu.Lock()
+	g.references[od.ID] = od.Reference
+	g.mu.Unlock()
+}
+
+// HandleEvent reports if the event changed a group
+//
+// ADDED_TO_GROUP adds the order and creates its group, CANCELLED_WITH_GROUP
+// takes the order out of the group, and CONCLUDED and CANCELLED forget it,
+// dropping the group with its last order. The other group events, as
+// EXECUTED_WITH_GROUP, only update the status of a group already known
+func (g *OrderGroups) HandleEvent(event events.Event) bool {
+	name := event.Name()
+	orderID := event.CorrelationID
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if (name == "CONCLUDED") || (name == "CANCELLED") {
+		delete(g.references, orderID)
+		return g.remove(orderID)
+	}
+	if !groupEvents[name] {
+		return false
+	}
+	groupID := groupIDFromMetadata(event.Metadata)
+	if groupID == "" {
+		groupID = g.groupOf[orderID]
+	}
+	if groupID == "" {
+		glg.Warn("[SDK] Orders OrderGroups event without group id: ", name, " orderID: ", orderID)
+		return false
+	}
+	if name == "ADDED_TO_GROUP" {
+		if current, ok := g.groupOf[orderID]; ok && current != groupID {
+			g.remove(orderID)
+		}
+		group := g.group(groupID)
+		if _, ok := g.groupOf[orderID]; !ok {
+			group.Orders = append(group.Orders, orderID)
+			sort.Strings(group.Orders)
+		}
+		g.groupOf[orderID] = groupID
+		group.Status = name
+		return true
+	}
+	group, ok := g.groups[groupID]
+	if !ok {
+		glg.Warn("[SDK] Orders OrderGroups event of unknown group: ", name, " groupId: ", groupID)
+		return false
+	}
+	group.Status = name
+	if name == "CANCELLED_WITH_GROUP" {
+		g.remove(orderID)
+	}
+	return true
+}
+
+// GroupOf returns the group of an order
+func (g *OrderGroups) GroupOf(orderID string) (group OrderGroup, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	groupID, ok := g.groupOf[orderID]
+	if !ok {
+		return
+	}
+	return g.copy(groupID), true
+}
+
+// Group returns a group by its id
+func (g *OrderGroups) Group(groupID string) (group OrderGroup, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok = g.groups[groupID]; !ok {
+		return
+	}
+	return g.copy(groupID), true
+}
+
+// SameGroup returns the ids of the other orders in the group of
+// an order, to be shown together on the expedition screen
+func (g *OrderGroups) SameGroup(orderID string) (orderIDs []string) {
+	group, ok := g.GroupOf(orderID)
+	if !ok {
+		return
+	}
+	for _, id := range group.Orders {
+		if id != orderID {
+			orderIDs = append(orderIDs, id)
+		}
+	}
+	return
+}
+
+// Dispatch sets the dispatch status of every order in the group,
+// orders not tracked fail with ErrOrderReferenceNotSpecified
+func (g *OrderGroups) Dispatch(service Service, groupID string) []GroupResult {
+	return g.each(groupID, service.SetDispatchStatus)
+}
+
+// ReadyToDeliver sets the ready to deliver status of every order in the group,
+// orders not tracked fail with ErrOrderReferenceNotSpecified
+func (g *OrderGroups) ReadyToDeliver(service Service, groupID string) []GroupResult {
+	return g.each(groupID, service.SetReadyToDeliverStatus)
+}
+
+func (g *OrderGroups) each(groupID string, setStatus func(reference string) error) (results []GroupResult) {
+	group, ok := g.Group(groupID)
+	if !ok {
+		glg.Warn("[SDK] Orders OrderGroups unknown group: ", groupID)
+		return
+	}
+	for _, orderID := range group.Orders {
+		g.mu.Lock()
+		res := GroupResult{OrderID: orderID, Reference: g.references[orderID]}
+		g.mu.Unlock()
+		if res.Reference == "" {
+			res.Err = ErrOrderReferenceNotSpecified
+			glg.Error("[SDK] Orders OrderGroups: ", res.Err.Error(), " orderID: ", orderID)
+		} else {
+			res.Err = setStatus(res.Reference)
+		}
+		results = append(results, res)
+	}
+	return
+}
+
+func (g *OrderGroups) group(groupID string) *OrderGroup {
+	group, ok := g.groups[groupID]
+	if !ok {
+		group = &OrderGroup{ID: groupID}
+		g.groups[groupID] = group
+	}
+	return group
+}
+
+func (g *OrderGroups) copy(groupID string) OrderGroup {
+	group := *g.groups[groupID]
+	group.Orders = append([]string{}, group.Orders...)
+	return group
+}
+
+// remove takes the order out of its group, empty groups are forgotten
+func (g *OrderGroups) remove(orderID string) bool {
+	groupID, ok := g.groupOf[orderID]
+	if !ok {
+		return false
+	}
+	delete(g.groupOf, orderID)
+	group := g.groups[groupID]
+	for i, id := range group.Orders {
+		if id == orderID {
+			group.Orders = append(group.Orders[:i], group.Orders[i+1:]...)
+			break
+		}
+	}
+	if len(group.Orders) == 0 {
+		delete(g.groups, groupID)
+	}
+	return true
+}
+
+func groupIDFromMetadata(metadata map[string]interface{}) string {
+	for _, key := range groupMetadataKeys {
+		if groupID, ok := metadata[key].(string); ok && groupID != "" {
+			return groupID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package orders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func groupEvent(code, orderID, groupID string) events.Event {
+	ev := events.Event{Code: code, CorrelationID: orderID}
+	if groupID != "" {
+		ev.Metadata = map[string]interface{}{"groupId": groupID}
+	}
+	return ev
+}
+
+func TestOrderGroups_Membership(t *testing.T) {
+	g := NewOrderGroups()
+	assert.True(t, g.HandleEvent(groupEvent("ATG", "a", "g1")))
+	assert.True(t, g.HandleEvent(groupEvent("ADDED_TO_GROUP", "b", "g1")))
+	assert.True(t, g.HandleEvent(groupEvent("ATG", "c", "g1")))
+	assert.False(t, g.HandleEvent(groupEvent("ATG", "d", "")))
+	assert.False(t, g.HandleEvent(groupEvent("PLACED", "e", "g1")))
+	assert.Equal(t, []string{"b", "c"}, g.SameGroup("a"))
+
+	assert.True(t, g.HandleEvent(groupEvent("AWG", "a", "")))
+	group, ok := g.GroupOf("b")
+	assert.True(t, ok)
+	assert.Equal(t, "ASSIGNED_WITH_GROUP", group.Status)
+
+	assert.True(t, g.HandleEvent(groupEvent("CWG", "b", "g1")))
+	assert.True(t, g.HandleEvent(groupEvent("CANCELLED", "c", "")))
+	assert.Equal(t, 0, len(g.SameGroup("a")))
+	_, ok = g.GroupOf("b")
+	assert.False(t, ok)
+
+	// executed orders stay in the group until they conclude
+	assert.True(t, g.HandleEvent(groupEvent("EWG", "a", "g1")))
+	group, ok = g.GroupOf("a")
+	assert.True(t, ok)
+	assert.Equal(t, "EXECUTED_WITH_GROUP", group.Status)
+	assert.Equal(t, []string{"a"}, group.Orders)
+	assert.True(t, g.HandleEvent(groupEvent("CONCLUDED", "a", "")))
+	_, ok = g.Group("g1")
+	assert.False(t, ok)
+	assert.False(t, g.HandleEvent(groupEvent("CONCLUDED", "a", "")))
+}
+
+func TestOrderGroups_UnknownGroup(t *testing.T) {
+	g := NewOrderGroups()
+	assert.False(t, g.HandleEvent(groupEvent("AWG", "a", "g1")))
+	assert.False(t, g.HandleEvent(groupEvent("COLLECTED_IN_GROUP", "a", "g1")))
+	assert.False(t, g.HandleEvent(groupEvent("EWG", "a", "g1")))
+	_, ok := g.Group("g1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(g.groups))
+}
+
+func TestOrderGroups_MovedToAnotherGroup(t *testing.T) {
+	g := NewOrderGroups()
+	g.HandleEvent(groupEvent("ATG", "a", "g1"))
+	g.HandleEvent(groupEvent("ATG", "b", "g1"))
+	g.HandleEvent(groupEvent("ATG", "a", "g2"))
+	group, ok := g.GroupOf("a")
+	assert.True(t, ok)
+	assert.Equal(t, "g2", group.ID)
+	assert.Equal(t, []string{"a"}, group.Orders)
+	group, _ = g.Group("g1")
+	assert.Equal(t, []string{"b"}, group.Orders)
+}
+
+func TestOrderGroups_Dispatch(t *testing.T) {
+	var dispatched []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dispatched = append(dispatched, r.URL.Path)
+			if strings.Contains(r.URL.Path, "/b/") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	g := NewOrderGroups()
+	// group events carry the order id, the status is sent with the reference
+	g.Track(OrderDetails{ID: "id_a", Reference: "a"})
+	g.Track(OrderDetails{ID: "id_b", Reference: "b"})
+	g.HandleEvent(groupEvent("ATG", "id_a", "g1"))
+	g.HandleEvent(groupEvent("ATG", "id_b", "g1"))
+	g.HandleEvent(groupEvent("ATG", "id_c", "g1"))
+	results := g.Dispatch(ordersService, "g1")
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, GroupResult{OrderID: "id_a", Reference: "a"}, results[0])
+	assert.NotNil(t, results[1].Err)
+	assert.Equal(t, ErrOrderReferenceNotSpecified, results[2].Err)
+	assert.Equal(t, []string{"/v1.0/orders/a/statuses/dispatch", "/v1.0/orders/b/statuses/dispatch"}, dispatched)
+	results = g.ReadyToDeliver(ordersService, "g1")
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, "/v2.0/orders/a/statuses/readyToDeliver", dispatched[2])
+	assert.Equal(t, 0, len(g.Dispatch(ordersService, "unknown")))
+
+	assert.True(t, g.HandleEvent(groupEvent("CONCLUDED", "id_a", "")))
+	assert.Equal(t, 1, len(g.references))
+}
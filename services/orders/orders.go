@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
@@ -57,17 +58,24 @@ type (
 		ClientCancellationStatus(reference string, accepted bool) error
 		Tracking(orderUUID string) (TrackingResponse, error)
 		DeliveryInformation(orderUUID string) (DeliveryInformationResponse, error)
+		DriverAvailability(orderReference string) (DriverAvailability, error)
+		RequestDriver(orderReference, quoteID string) error
+		GetUpdateRequest(orderReference string) (OrderUpdate, error)
+		UpdateRequestStatus(orderReference string, accepted bool) error
+		ExtendPreparationTime(orderReference string, increase time.Duration) error
+		NotifyDelay(orderReference string, delay time.Duration) error
 	}
 
 	ordersService struct {
 		adapter adapters.Http
 		auth    auth.Service
+		kinds   *orderKinds
 	}
 )
 
 // New returns a new order service
 func New(adapter adapters.Http, authService auth.Service) *ordersService {
-	return &ordersService{adapter, authService}
+	return &ordersService{adapter: adapter, auth: authService, kinds: newOrderKinds()}
 }
 
 func (o *ordersService) GetDetails(orderReference string) (od OrderDetails, err error) {
@@ -95,7 +103,12 @@ func (o *ordersService) GetDetails(orderReference string) (od OrderDetails, err
 		glg.Error("[SDK] Orders GetDetails err: ", err)
 		return
 	}
-	return od, json.Unmarshal(resp, &od)
+	if err = json.Unmarshal(resp, &od); err != nil {
+		glg.Error("[SDK] Orders GetDetails Unmarshal: ", err.Error())
+		return
+	}
+	o.kinds.remember(orderReference, od)
+	return
 }
 
 func (o *ordersService) SetIntegrateStatus(orderReference string) (err error) {
@@ -154,12 +167,23 @@ func (o *ordersService) SetConfirmStatus(orderReference string) (err error) {
 	return
 }
 
+// SetDispatchStatus informa que o pedido saiu para entrega
+//
+// orders previously fetched with GetDetails on the same service are
+// rejected when their type does not support the dispatch status, orders
+// fetched by another instance, or more than 6 hours ago, are not checked
 func (o *ordersService) SetDispatchStatus(orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
 		glg.Error("[SDK] Orders SetDispatchStatus: ", err.Error())
 		return
 	}
+	if kind, ok := o.kinds.get(orderReference); ok {
+		if err = kind.canDispatch(); err != nil {
+			glg.Error("[SDK] Orders SetDispatchStatus: ", err.Error(), " orderReference: ", orderReference)
+			return
+		}
+	}
 	err = o.auth.Validate()
 	if err != nil {
 		glg.Error("[SDK] Orders SetDispatchStatus auth.Validate: ", err.Error())
@@ -179,15 +203,27 @@ func (o *ordersService) SetDispatchStatus(orderReference string) (err error) {
 		glg.Error("[SDK] Orders SetDispatchStatus err: ", err)
 		return
 	}
+	o.kinds.forget(orderReference)
 	return
 }
 
+// SetReadyToDeliverStatus informa que o pedido esta pronto para ser retirado
+//
+// orders previously fetched with GetDetails on the same service are
+// rejected when their type does not support the ready to deliver status,
+// orders fetched by another instance, or more than 6 hours ago, are not checked
 func (o *ordersService) SetReadyToDeliverStatus(orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
 		glg.Error("[SDK] Orders SetReadyToDeliverStatus: ", err.Error())
 		return
 	}
+	if kind, ok := o.kinds.get(orderReference); ok {
+		if err = kind.canSetReadyToDeliver(); err != nil {
+			glg.Error("[SDK] Orders SetReadyToDeliverStatus: ", err.Error(), " orderReference: ", orderReference)
+			return
+		}
+	}
 	err = o.auth.Validate()
 	if err != nil {
 		glg.Error("[SDK] Orders SetReadyToDeliverStatus auth.Validate: ", err.Error())
@@ -207,6 +243,7 @@ func (o *ordersService) SetReadyToDeliverStatus(orderReference string) (err erro
 		glg.Error("[SDK] Orders SetReadyToDeliverStatus err: ", err)
 		return
 	}
+	o.kinds.forget(orderReference)
 	return
 }
 
@@ -243,6 +280,7 @@ func (o *ordersService) SetCancelStatus(orderReference, code string) (err error)
 		glg.Error("[SDK] Orders SetCancelStatus err: ", err)
 		return
 	}
+	o.kinds.forget(orderReference)
 	return
 }
 
@@ -341,6 +379,235 @@ func (o *ordersService) DeliveryInformation(orderUUID string) (di DeliveryInform
 	return di, json.Unmarshal(resp, &di)
 }
 
+// DriverAvailability consulta se ha entregador iFood disponivel e a cotacao da entrega
+//
+// used on orders delivered by the merchant, the returned quote ID
+// is used on RequestDriver before it expires
+func (o *ordersService) DriverAvailability(orderReference string) (da DriverAvailability, err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		glg.Error("[SDK] Orders DriverAvailability: ", err.Error())
+		return
+	}
+	if err = o.auth.Validate(); err != nil {
+		glg.Error("[SDK] Orders DriverAvailability auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s/delivery-availabilities", v2Endpoint, orderReference)
+	resp, status, err := o.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Orders DriverAvailability adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Orders DriverAvailability status code: ", status, " orderReference: ", orderReference)
+		err = fmt.Errorf("Order reference '%s' could not get driver availability", orderReference)
+		if apiErr := parseAPIError(resp); apiErr != "" {
+			err = fmt.Errorf("%s, code: '%s'", err.Error(), apiErr)
+		}
+		glg.Error("[SDK] Orders DriverAvailability err: ", err)
+		return
+	}
+	return da, json.Unmarshal(resp, &da)
+}
+
+// RequestDriver solicita um entregador iFood para um pedido entregue pelo merchant
+//
+// the outcome arrives later as REQUEST_DRIVER_SUCCESS, REQUEST_DRIVER_FAILED
+// and ASSIGN_DRIVER events, see DriverRequests
+func (o *ordersService) RequestDriver(orderReference, quoteID string) (err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		glg.Error("[SDK] Orders RequestDriver: ", err.Error())
+		return
+	}
+	if quoteID == "" {
+		err = ErrQuoteNotSpecified
+		glg.Error("[SDK] Orders RequestDriver: ", err.Error())
+		return
+	}
+	if err = o.auth.Validate(); err != nil {
+		glg.Error("[SDK] Orders RequestDriver auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s/request-driver", v2Endpoint, orderReference)
+	reader, err := httpadapter.NewJsonReader(driverRequest{QuoteID: quoteID})
+	if err != nil {
+		glg.Error("[SDK] Orders RequestDriver NewJsonReader error: ", err.Error())
+		return
+	}
+	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	if err != nil {
+		glg.Error("[SDK] Orders RequestDriver adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusAccepted {
+		glg.Error("[SDK] Orders RequestDriver status code: ", status, " orderReference: ", orderReference)
+		err = fmt.Errorf("Order reference '%s' could not request driver with quote '%s'", orderReference, quoteID)
+		if apiErr := parseAPIError(resp); apiErr != "" {
+			err = fmt.Errorf("%s, code: '%s'", err.Error(), apiErr)
+		}
+		glg.Error("[SDK] Orders RequestDriver err: ", err)
+		return
+	}
+	return
+}
+
+// GetUpdateRequest retorna a alteracao de itens ou endereco solicitada
+// apos um evento UPDATE_REQUESTED
+//
+// use DiffOrder to compare it with the current order
+func (o *ordersService) GetUpdateRequest(orderReference string) (ou OrderUpdate, err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		glg.Error("[SDK] Orders GetUpdateRequest: ", err.Error())
+		return
+	}
+	if err = o.auth.Validate(); err != nil {
+		glg.Error("[SDK] Orders GetUpdateRequest auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s/update-request", v2Endpoint, orderReference)
+	resp, status, err := o.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Orders GetUpdateRequest adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusOK {
+		glg.Error("[SDK] Orders GetUpdateRequest status code: ", status, " orderReference: ", orderReference)
+		err = fmt.Errorf("Order reference '%s' could not get update request", orderReference)
+		if apiErr := parseAPIError(resp); apiErr != "" {
+			err = fmt.Errorf("%s, code: '%s'", err.Error(), apiErr)
+		}
+		glg.Error("[SDK] Orders GetUpdateRequest err: ", err)
+		return
+	}
+	return ou, json.Unmarshal(resp, &ou)
+}
+
+// UpdateRequestStatus aceita ou recusa a alteracao solicitada do pedido
+//
+// reference: order reference id
+// accepted: aceitacao pelo e-PDV da alteracao do pedido
+func (o *ordersService) UpdateRequestStatus(orderReference string, accepted bool) (err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		glg.Error("[SDK] Orders UpdateRequestStatus: ", err.Error())
+		return
+	}
+	if err = o.auth.Validate(); err != nil {
+		glg.Error("[SDK] Orders UpdateRequestStatus auth.Validate: ", err.Error())
+		return
+	}
+	updateStatus := "deny"
+	if accepted {
+		updateStatus = "accept"
+	}
+	headers := make(map[string]string)
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s/update-request/%s", v2Endpoint, orderReference, updateStatus)
+	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	if err != nil {
+		glg.Error("[SDK] Orders UpdateRequestStatus adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusAccepted {
+		glg.Error("[SDK] Orders UpdateRequestStatus status code: ", status, " orderReference: ", orderReference)
+		err = fmt.Errorf(
+			"Order reference '%s' could not %s update request", orderReference, updateStatus)
+		if apiErr := parseAPIError(resp); apiErr != "" {
+			err = fmt.Errorf("%s, code: '%s'", err.Error(), apiErr)
+		}
+		glg.Error("[SDK] Orders UpdateRequestStatus err: ", err)
+		return
+	}
+	return
+}
+
+// ExtendPreparationTime aumenta o tempo de preparo do pedido
+//
+// increase must be a multiple of PreparationTimeStep up to MaxPreparationTimeIncrease
+func (o *ordersService) ExtendPreparationTime(orderReference string, increase time.Duration) (err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		glg.Error("[SDK] Orders ExtendPreparationTime: ", err.Error())
+		return
+	}
+	if err = verifyIncrement(increase, MaxPreparationTimeIncrease); err != nil {
+		glg.Error("[SDK] Orders ExtendPreparationTime verifyIncrement: ", err.Error())
+		return
+	}
+	body := preparationTimeIncrease{Seconds: int(increase / time.Second)}
+	return o.postTiming("ExtendPreparationTime", orderReference, "preparation-time", body)
+}
+
+// NotifyDelay informa ao cliente que o pedido vai atrasar
+//
+// delay must be a multiple of PreparationTimeStep up to MaxDelay
+func (o *ordersService) NotifyDelay(orderReference string, delay time.Duration) (err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		glg.Error("[SDK] Orders NotifyDelay: ", err.Error())
+		return
+	}
+	if err = verifyIncrement(delay, MaxDelay); err != nil {
+		glg.Error("[SDK] Orders NotifyDelay verifyIncrement: ", err.Error())
+		return
+	}
+	body := delayNotification{Seconds: int(delay / time.Second)}
+	return o.postTiming("NotifyDelay", orderReference, "delay", body)
+}
+
+func (o *ordersService) postTiming(method, orderReference, path string, body interface{}) (err error) {
+	if err = o.auth.Validate(); err != nil {
+		glg.Error("[SDK] Orders ", method, " auth.Validate: ", err.Error())
+		return
+	}
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
+	endpoint := fmt.Sprintf("%s/%s/%s", v2Endpoint, orderReference, path)
+	reader, err := httpadapter.NewJsonReader(body)
+	if err != nil {
+		glg.Error("[SDK] Orders ", method, " NewJsonReader error: ", err.Error())
+		return
+	}
+	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	if err != nil {
+		glg.Error("[SDK] Orders ", method, " adapter.DoRequest error: ", err.Error())
+		return
+	}
+	if status != http.StatusAccepted {
+		glg.Error("[SDK] Orders ", method, " status code: ", status, " orderReference: ", orderReference)
+		err = fmt.Errorf("Order reference '%s' could not update %s", orderReference, path)
+		if apiErr := parseAPIError(resp); apiErr != "" {
+			err = fmt.Errorf("%s, code: '%s'", err.Error(), apiErr)
+		}
+		glg.Error("[SDK] Orders ", method, " err: ", err)
+		return
+	}
+	return
+}
+
+// parseAPIError returns the error code of an API error body, if any
+func parseAPIError(resp []byte) string {
+	apiErr := apiError{}
+	if err := json.Unmarshal(resp, &apiErr); err != nil {
+		return ""
+	}
+	if apiErr.Error.Code != "" {
+		return apiErr.Error.Code
+	}
+	return apiErr.Details.Code
+}
+
 func verifyCancel(reference, code string) (err error) {
 	if reference == "" {
 		err = ErrOrderReferenceNotSpecified
@@ -0,0 +1,158 @@
+package orders
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// OrderTypeDelivery order delivered to the customer address
+	OrderTypeDelivery OrderType = "DELIVERY"
+	// OrderTypeTakeout order picked up by the customer
+	OrderTypeTakeout OrderType = "TAKEOUT"
+	// OrderTypeToGo legacy name of takeout orders
+	OrderTypeToGo OrderType = "TOGO"
+	// OrderTypeIndoor order consumed inside the store
+	OrderTypeIndoor OrderType = "INDOOR"
+
+	// DeliveredByIfood delivery done by the iFood logistics
+	DeliveredByIfood = "IFOOD"
+	// DeliveredByMerchant delivery done by the merchant own couriers
+	DeliveredByMerchant = "MERCHANT"
+
+	// orderKindTTL how long the kind of a fetched order guards its status changes
+	orderKindTTL = 6 * time.Hour
+	// maxOrderKinds orders remembered at most, the oldest are dropped first
+	maxOrderKinds = 10000
+)
+
+var (
+	// ErrDispatchNotSupported the order type can not be dispatched
+	ErrDispatchNotSupported = errors.New("Order type does not support 'dispatch' status")
+	// ErrReadyToDeliverNotSupported the order type can not be set as ready to deliver
+	ErrReadyToDeliverNotSupported = errors.New("Order type does not support 'ready to deliver' status")
+)
+
+type (
+	// OrderType of an order
+	OrderType string
+
+	// orderKind what ordersService remembers of each order to guard status changes
+	orderKind struct {
+		orderType   OrderType
+		deliveredBy string
+		seen        time.Time
+	}
+
+	// orderKinds kinds of the orders fetched by one ordersService, entries
+	// expire after orderKindTTL and at most maxOrderKinds are kept, so
+	// orders that conclude or are cancelled elsewhere do not pile up
+	orderKinds struct {
+		mu    sync.Mutex
+		now   func() time.Time
+		kinds map[string]orderKind
+	}
+)
+
+// IsTakeout reports if the customer picks up the order
+func (t OrderType) IsTakeout() bool {
+	return (t == OrderTypeTakeout) || (t == OrderTypeToGo)
+}
+
+// PickupCode returns the code the customer or courier shows when picking up
+func (od OrderDetails) PickupCode() string {
+	switch {
+	case od.Takeout != nil:
+		return od.Takeout.PickupCode
+	case od.Delivery != nil:
+		return od.Delivery.PickupCode
+	}
+	return ""
+}
+
+// DeliveredBy returns who delivers a DELIVERY order, empty when unknown
+func (od OrderDetails) DeliveredBy() string {
+	if od.Delivery == nil {
+		return ""
+	}
+	return od.Delivery.DeliveredBy
+}
+
+// CanDispatch verifies if the order supports the dispatch status,
+// only deliveries done by the merchant are dispatched
+func (od OrderDetails) CanDispatch() error {
+	return orderKind{orderType: od.Type, deliveredBy: od.DeliveredBy()}.canDispatch()
+}
+
+// CanSetReadyToDeliver verifies if the order supports the ready to deliver status,
+// deliveries done by the merchant are dispatched instead
+func (od OrderDetails) CanSetReadyToDeliver() error {
+	return orderKind{orderType: od.Type, deliveredBy: od.DeliveredBy()}.canSetReadyToDeliver()
+}
+
+func (k orderKind) canDispatch() error {
+	if k.orderType.IsTakeout() || (k.orderType == OrderTypeIndoor) {
+		return ErrDispatchNotSupported
+	}
+	if (k.orderType == OrderTypeDelivery) && (k.deliveredBy == DeliveredByIfood) {
+		return ErrDispatchNotSupported
+	}
+	return nil
+}
+
+func (k orderKind) canSetReadyToDeliver() error {
+	if (k.orderType == OrderTypeDelivery) && (k.deliveredBy == DeliveredByMerchant) {
+		return ErrReadyToDeliverNotSupported
+	}
+	return nil
+}
+
+func newOrderKinds() *orderKinds {
+	return &orderKinds{now: time.Now, kinds: make(map[string]orderKind)}
+}
+
+func (o *orderKinds) remember(reference string, od OrderDetails) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := o.now()
+	if _, ok := o.kinds[reference]; !ok && (len(o.kinds) >= maxOrderKinds) {
+		o.prune(now)
+	}
+	o.kinds[reference] = orderKind{orderType: od.Type, deliveredBy: od.DeliveredBy(), seen: now}
+}
+
+// prune drops the expired kinds, or the oldest one when none expired
+func (o *orderKinds) prune(now time.Time) {
+	oldest := ""
+	for reference, k := range o.kinds {
+		if now.Sub(k.seen) > orderKindTTL {
+			delete(o.kinds, reference)
+			continue
+		}
+		if (oldest == "") || k.seen.Before(o.kinds[oldest].seen) {
+			oldest = reference
+		}
+	}
+	if len(o.kinds) >= maxOrderKinds {
+		delete(o.kinds, oldest)
+	}
+}
+
+func (o *orderKinds) forget(reference string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.kinds, reference)
+}
+
+// get returns the kind of an order seen on GetDetails, orders never
+// fetched or fetched more than orderKindTTL ago are unknown and not guarded
+func (o *orderKinds) get(reference string) (k orderKind, ok bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if k, ok = o.kinds[reference]; ok && (o.now().Sub(k.seen) > orderKindTTL) {
+		delete(o.kinds, reference)
+		return orderKind{}, false
+	}
+	return
+}
@@ -0,0 +1,127 @@
+package orders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+const takeoutDetails = `{
+	"id": "reference_id",
+	"type": "TAKEOUT",
+	"takeout": {
+		"mode": "DEFAULT",
+		"takeoutDateTime": "2021-03-01T19:30:00Z",
+		"pickupCode": "4321"
+	}
+}`
+
+func TestOrderDetails_TypeSpecifics(t *testing.T) {
+	od := OrderDetails{}
+	assert.Nil(t, json.Unmarshal([]byte(takeoutDetails), &od))
+	assert.Equal(t, OrderTypeTakeout, od.Type)
+	assert.True(t, od.Type.IsTakeout())
+	assert.Equal(t, "4321", od.PickupCode())
+	assert.Equal(t, "", od.DeliveredBy())
+	indoor := OrderDetails{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"type":"INDOOR","indoor":{"mode":"TABLE","table":"12"}}`), &indoor))
+	assert.Equal(t, "12", indoor.Indoor.Table)
+}
+
+func TestOrderDetails_CanDispatch(t *testing.T) {
+	assert.Nil(t, OrderDetails{Type: OrderTypeDelivery}.CanDispatch())
+	assert.Nil(t, OrderDetails{Type: OrderTypeDelivery, Delivery: &Delivery{DeliveredBy: DeliveredByMerchant}}.CanDispatch())
+	assert.Equal(t, ErrDispatchNotSupported, OrderDetails{Type: OrderTypeDelivery, Delivery: &Delivery{DeliveredBy: DeliveredByIfood}}.CanDispatch())
+	assert.Equal(t, ErrDispatchNotSupported, OrderDetails{Type: OrderTypeTakeout}.CanDispatch())
+	assert.Equal(t, ErrDispatchNotSupported, OrderDetails{Type: OrderTypeToGo}.CanDispatch())
+	assert.Equal(t, ErrDispatchNotSupported, OrderDetails{Type: OrderTypeIndoor}.CanDispatch())
+}
+
+func TestOrderDetails_CanSetReadyToDeliver(t *testing.T) {
+	assert.Nil(t, OrderDetails{Type: OrderTypeTakeout}.CanSetReadyToDeliver())
+	assert.Nil(t, OrderDetails{Type: OrderTypeIndoor}.CanSetReadyToDeliver())
+	assert.Nil(t, OrderDetails{Type: OrderTypeDelivery, Delivery: &Delivery{DeliveredBy: DeliveredByIfood}}.CanSetReadyToDeliver())
+	assert.Equal(t, ErrReadyToDeliverNotSupported, OrderDetails{Type: OrderTypeDelivery, Delivery: &Delivery{DeliveredBy: DeliveredByMerchant}}.CanSetReadyToDeliver())
+}
+
+func TestSetDispatchStatus_TakeoutRejected(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			switch r.URL.Path {
+			case "/v3.0/orders/reference_id":
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, takeoutDetails)
+			case "/v2.0/orders/reference_id/statuses/readyToDeliver":
+				w.WriteHeader(http.StatusAccepted)
+			default:
+				t.Errorf("unexpected request %s", r.URL.Path)
+			}
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	_, err := ordersService.GetDetails("reference_id")
+	assert.Nil(t, err)
+	err = ordersService.SetDispatchStatus("reference_id")
+	assert.Equal(t, ErrDispatchNotSupported, err)
+	err = ordersService.SetReadyToDeliverStatus("reference_id")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestSetReadyToDeliverStatus_MerchantDeliveryRejected(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v3.0/orders/reference_id", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"id":"reference_id","type":"DELIVERY","delivery":{"deliveredBy":"MERCHANT"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	_, err := ordersService.GetDetails("reference_id")
+	assert.Nil(t, err)
+	err = ordersService.SetReadyToDeliverStatus("reference_id")
+	assert.Equal(t, ErrReadyToDeliverNotSupported, err)
+}
+
+func TestOrderKinds_Bounded(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	kinds := newOrderKinds()
+	kinds.now = func() time.Time { return now }
+	kinds.remember("takeout", OrderDetails{Type: OrderTypeTakeout})
+	_, ok := kinds.get("takeout")
+	assert.True(t, ok)
+	now = now.Add(orderKindTTL + time.Minute)
+	_, ok = kinds.get("takeout")
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(kinds.kinds))
+
+	for i := 0; i < maxOrderKinds+5; i++ {
+		now = now.Add(time.Second)
+		kinds.remember(strconv.Itoa(i), OrderDetails{Type: OrderTypeDelivery})
+	}
+	assert.Equal(t, maxOrderKinds, len(kinds.kinds))
+	_, ok = kinds.get("4")
+	assert.False(t, ok)
+	_, ok = kinds.get("5")
+	assert.True(t, ok)
+}
@@ -0,0 +1,181 @@
+package orders
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/kpango/glg"
+)
+
+const (
+	// PreparationTimeStep preparation time and delays are informed in steps of 5 minutes
+	PreparationTimeStep = 5 * time.Minute
+	// MaxPreparationTimeIncrease largest increase accepted by ExtendPreparationTime
+	MaxPreparationTimeIncrease = 30 * time.Minute
+	// MaxDelay largest delay accepted by NotifyDelay
+	MaxDelay = 60 * time.Minute
+)
+
+type (
+	// Deadlines keeps the promised ready time of the orders in the kitchen,
+	// following preparation time changes and delays through the polled events
+	Deadlines struct {
+		mu     sync.Mutex
+		orders map[string]deadline
+	}
+
+	// LateOrder order past its promised ready time
+	LateOrder struct {
+		Reference string
+		Deadline  time.Time
+		Late      time.Duration
+	}
+
+	deadline struct {
+		start       time.Time
+		preparation time.Duration
+		extra       time.Duration
+	}
+)
+
+// ReadyDeadline returns when the order was promised to be ready,
+// its creation (or preparation start when scheduled) plus the preparation time
+func (od OrderDetails) ReadyDeadline() (t time.Time, err error) {
+	start, preparation, err := od.preparationWindow()
+	if err != nil {
+		return
+	}
+	return start.Add(preparation), nil
+}
+
+// IsLate reports if the order is past its ready deadline at now
+func (od OrderDetails) IsLate(now time.Time) bool {
+	deadline, err := od.ReadyDeadline()
+	return (err == nil) && now.After(deadline)
+}
+
+func (od OrderDetails) preparationWindow() (start time.Time, preparation time.Duration, err error) {
+	if preparation, err = od.PreparationTime(); err != nil {
+		return
+	}
+	if od.IsScheduled() {
+		start, err = od.PreparationStart()
+		return
+	}
+	start, err = parseOrderTime(od.Createdat)
+	return
+}
+
+func verifyIncrement(d, max time.Duration) error {
+	if (d <= 0) || (d > max) || (d%PreparationTimeStep != 0) {
+		return ErrInvalidIncrement
+	}
+	return nil
+}
+
+// NewDeadlines returns an empty deadline tracker
+func NewDeadlines() *Deadlines {
+	return &Deadlines{orders: make(map[string]deadline)}
+}
+
+// Track adds an order to the kitchen
+func (d *Deadlines) Track(od OrderDetails) (err error) {
+	start, preparation, err := od.preparationWindow()
+	if err != nil {
+		glg.Error("[SDK] Orders Deadlines Track: ", err.Error())
+		return
+	}
+	d.mu.Lock()
+	d.orders[od.ID] = deadline{start: start, preparation: preparation}
+	d.mu.Unlock()
+	return
+}
+
+// Deadline returns the current ready deadline of a tracked order
+func (d *Deadlines) Deadline(reference string) (t time.Time, ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	dl, ok := d.orders[reference]
+	if !ok {
+		return
+	}
+	return dl.at(), true
+}
+
+// Late returns the tracked orders past their deadline, the most late first
+func (d *Deadlines) Late(now time.Time) (late []LateOrder) {
+	d.mu.Lock()
+	for reference, dl := range d.orders {
+		if at := dl.at(); now.After(at) {
+			late = append(late, LateOrder{Reference: reference, Deadline: at, Late: now.Sub(at)})
+		}
+	}
+	d.mu.Unlock()
+	sort.Slice(late, func(i, j int) bool {
+		if late[i].Deadline.Equal(late[j].Deadline) {
+			return late[i].Reference < late[j].Reference
+		}
+		return late[i].Deadline.Before(late[j].Deadline)
+	})
+	return
+}
+
+// HandleEvent reports if the event changed a tracked order
+//
+// CHANGE_PREPARATION_TIME replaces the preparation time (preparationTimeInSeconds)
+// or extends it (additionalTimeInSeconds), DELAY_NOTIFICATION extends it by
+// delayInSeconds; orders leave the kitchen when ready, dispatched or cancelled
+func (d *Deadlines) HandleEvent(event events.Event) bool {
+	reference := event.CorrelationID
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	dl, ok := d.orders[reference]
+	if !ok {
+		return false
+	}
+	switch event.Name() {
+	case "CHANGE_PREPARATION_TIME":
+		if seconds, ok := metadataSeconds(event.Metadata, "preparationTimeInSeconds"); ok {
+			dl.preparation = seconds
+		} else if seconds, ok := metadataSeconds(event.Metadata, "additionalTimeInSeconds"); ok {
+			dl.extra += seconds
+		} else {
+			return false
+		}
+		d.orders[reference] = dl
+	case "DELAY_NOTIFICATION":
+		seconds, ok := metadataSeconds(event.Metadata, "delayInSeconds")
+		if !ok {
+			return false
+		}
+		dl.extra += seconds
+		d.orders[reference] = dl
+	case "READY_TO_DELIVER", "DISPATCHED", "CANCELLED", "CONCLUDED":
+		delete(d.orders, reference)
+	default:
+		return false
+	}
+	return true
+}
+
+func (dl deadline) at() time.Time {
+	return dl.start.Add(dl.preparation + dl.extra)
+}
+
+// metadataSeconds reads a duration in seconds sent either as number or string
+func metadataSeconds(metadata map[string]interface{}, key string) (d time.Duration, ok bool) {
+	switch v := metadata[key].(type) {
+	case float64:
+		return time.Duration(v) * time.Second, true
+	case string:
+		seconds, err := strconv.Atoi(v)
+		if err != nil {
+			return
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	return
+}
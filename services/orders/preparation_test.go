@@ -0,0 +1,131 @@
+package orders
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendPreparationTime_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2.0/orders/reference_id/preparation-time", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodPost)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"additionalTimeInSeconds":600}`, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	assert.Nil(t, ordersService.ExtendPreparationTime("reference_id", 10*time.Minute))
+}
+
+func TestExtendPreparationTime_InvalidIncrement(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	ordersService := New(adapter, &am)
+	assert.Equal(t, ErrOrderReferenceNotSpecified, ordersService.ExtendPreparationTime("", 5*time.Minute))
+	for _, d := range []time.Duration{0, -5 * time.Minute, 7 * time.Minute, 35 * time.Minute} {
+		assert.Equal(t, ErrInvalidIncrement, ordersService.ExtendPreparationTime("reference_id", d))
+	}
+}
+
+func TestNotifyDelay_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2.0/orders/reference_id/delay", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"delayInSeconds":2700}`, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	assert.Nil(t, ordersService.NotifyDelay("reference_id", 45*time.Minute))
+	assert.Equal(t, ErrInvalidIncrement, ordersService.NotifyDelay("reference_id", 65*time.Minute))
+}
+
+func TestNotifyDelay_StatusBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.NotifyDelay("reference_id", 5*time.Minute)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "delay")
+}
+
+func TestReadyDeadline(t *testing.T) {
+	od := OrderDetails{Createdat: "2021-03-01T19:30:00Z", Preparationtimeinseconds: "1200"}
+	deadline, err := od.ReadyDeadline()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 19, 50, 0, 0, time.UTC), deadline)
+	assert.False(t, od.IsLate(deadline))
+	assert.True(t, od.IsLate(deadline.Add(time.Second)))
+
+	od.OrderTiming = OrderTimingScheduled
+	od.Schedule = &Schedule{DeliveryDateTimeStart: "2021-03-01T21:00:00Z"}
+	deadline, err = od.ReadyDeadline()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 21, 0, 0, 0, time.UTC), deadline)
+
+	_, err = OrderDetails{Createdat: "2021-03-01T19:30:00Z"}.ReadyDeadline()
+	assert.NotNil(t, err)
+	assert.False(t, OrderDetails{}.IsLate(time.Now()))
+}
+
+func TestDeadlines(t *testing.T) {
+	d := NewDeadlines()
+	assert.NotNil(t, d.Track(OrderDetails{ID: "bad"}))
+	assert.Nil(t, d.Track(OrderDetails{ID: "a", Reference: "ref-a", Createdat: "2021-03-01T19:30:00Z", Preparationtimeinseconds: "1200"}))
+	assert.Nil(t, d.Track(OrderDetails{ID: "b", Reference: "ref-b", Createdat: "2021-03-01T19:40:00Z", Preparationtimeinseconds: "600"}))
+	now := time.Date(2021, 3, 1, 19, 55, 0, 0, time.UTC)
+	late := d.Late(now)
+	assert.Equal(t, 2, len(late))
+	assert.Equal(t, "a", late[0].Reference)
+	assert.Equal(t, 5*time.Minute, late[0].Late)
+	// orders are tracked by id, the correlation id of their events
+	_, ok := d.Deadline("ref-a")
+	assert.False(t, ok)
+	assert.False(t, d.HandleEvent(events.Event{Code: "DNO", CorrelationID: "ref-a",
+		Metadata: map[string]interface{}{"delayInSeconds": float64(600)}}))
+
+	assert.True(t, d.HandleEvent(events.Event{Code: "DNO", CorrelationID: "a",
+		Metadata: map[string]interface{}{"delayInSeconds": float64(600)}}))
+	assert.True(t, d.HandleEvent(events.Event{Code: "CPT", CorrelationID: "b",
+		Metadata: map[string]interface{}{"preparationTimeInSeconds": "1800"}}))
+	assert.False(t, d.HandleEvent(events.Event{Code: "CPT", CorrelationID: "b"}))
+	assert.False(t, d.HandleEvent(events.Event{Code: "DNO", CorrelationID: "c"}))
+	assert.Equal(t, 0, len(d.Late(now)))
+	deadline, ok := d.Deadline("b")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2021, 3, 1, 20, 10, 0, 0, time.UTC), deadline)
+
+	assert.True(t, d.HandleEvent(events.Event{Code: "RTD", CorrelationID: "a"}))
+	_, ok = d.Deadline("a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package orders
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// SponsorIfood benefit share paid by iFood
+	SponsorIfood = "IFOOD"
+	// SponsorMerchant benefit share paid by the merchant
+	SponsorMerchant = "MERCHANT"
+
+	// BenefitTargetCart benefit applied to the order subtotal
+	BenefitTargetCart = "CART"
+	// BenefitTargetDeliveryFee benefit applied to the delivery fee
+	BenefitTargetDeliveryFee = "DELIVERY_FEE"
+	// BenefitTargetItem benefit applied to a single item
+	BenefitTargetItem = "ITEM"
+)
+
+// Revenue breakdown of an order for reconciliation
+//
+// DeliveryFee is only accounted for when the merchant delivers the order,
+// benefits paid by iFood or other sponsors do not reduce the merchant revenue.
+type Revenue struct {
+	Subtotal         float64
+	DeliveryFee      float64
+	AdditionalFees   float64
+	IfoodBenefits    float64
+	MerchantBenefits float64
+	OtherBenefits    float64
+	Net              float64
+}
+
+// Revenue computes the net merchant revenue of an order
+func (od OrderDetails) Revenue() (r Revenue, err error) {
+	if r.Subtotal, err = parseField(od.Reference, "subTotal", od.Subtotal); err != nil {
+		return
+	}
+	if od.DeliveredBy() != DeliveredByIfood {
+		if r.DeliveryFee, err = parseField(od.Reference, "deliveryFee", od.Deliveryfee); err != nil {
+			return
+		}
+	}
+	for _, fee := range od.AdditionalFees {
+		value, err := parseField(od.Reference, "additionalFees.value", fee.Value)
+		if err != nil {
+			return r, err
+		}
+		r.AdditionalFees += value
+	}
+	for _, benefit := range od.Benefits {
+		for _, sponsorship := range benefit.SponsorshipValues {
+			value, err := parseField(od.Reference, "sponsorshipValues.value", sponsorship.Value)
+			if err != nil {
+				return r, err
+			}
+			switch sponsorship.Name {
+			case SponsorIfood:
+				r.IfoodBenefits += value
+			case SponsorMerchant:
+				r.MerchantBenefits += value
+			default:
+				r.OtherBenefits += value
+			}
+		}
+	}
+	r.Net = roundCents(r.Subtotal + r.DeliveryFee + r.AdditionalFees - r.MerchantBenefits)
+	return
+}
+
+// Change returns how much change the courier should take for a cash payment
+func (p Payment) Change() (change float64, err error) {
+	if p.ChangeFor == "" {
+		return
+	}
+	changeFor, err := ParseAmount(p.ChangeFor)
+	if err != nil {
+		err = fmt.Errorf("payment changeFor '%s' is invalid", p.ChangeFor)
+		return
+	}
+	value, err := ParseAmount(p.Value)
+	if err != nil {
+		err = fmt.Errorf("payment value '%s' is invalid", p.Value)
+		return
+	}
+	return roundCents(math.Max(changeFor-value, 0)), nil
+}
+
+func parseField(reference, field, value string) (amount float64, err error) {
+	if amount, err = ParseAmount(value); err != nil {
+		err = fmt.Errorf("Order '%s' field '%s' value '%s' is invalid", reference, field, value)
+	}
+	return
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
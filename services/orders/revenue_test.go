@@ -0,0 +1,87 @@
+package orders
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const benefitsDetails = `{
+	"reference": "reference_id",
+	"type": "DELIVERY",
+	"delivery": {"deliveredBy": "MERCHANT"},
+	"subTotal": "100.00",
+	"deliveryFee": "8.00",
+	"totalPrice": "93.00",
+	"benefits": [
+		{
+			"value": "15.00",
+			"target": "CART",
+			"sponsorshipValues": [
+				{"name": "IFOOD", "value": "10.00"},
+				{"name": "MERCHANT", "value": "5.00"}
+			]
+		},
+		{
+			"value": "3.00",
+			"target": "DELIVERY_FEE",
+			"sponsorshipValues": [
+				{"name": "MERCHANT", "value": "3.00"}
+			]
+		}
+	],
+	"additionalFees": [
+		{"type": "SMALL_ORDER_FEE", "value": "3.00", "description": "Taxa de pedido mínimo"}
+	],
+	"payments": [
+		{"name": "DINHEIRO", "code": "DIN", "value": "93.00", "prepaid": "false", "changeFor": "100.00"}
+	]
+}`
+
+func TestOrderDetails_Revenue(t *testing.T) {
+	od := OrderDetails{}
+	assert.Nil(t, json.Unmarshal([]byte(benefitsDetails), &od))
+	r, err := od.Revenue()
+	assert.Nil(t, err)
+	assert.Equal(t, Revenue{
+		Subtotal:         100,
+		DeliveryFee:      8,
+		AdditionalFees:   3,
+		IfoodBenefits:    10,
+		MerchantBenefits: 8,
+		Net:              103,
+	}, r)
+}
+
+func TestOrderDetails_Revenue_IfoodDelivery(t *testing.T) {
+	od := OrderDetails{}
+	assert.Nil(t, json.Unmarshal([]byte(benefitsDetails), &od))
+	od.Delivery.DeliveredBy = DeliveredByIfood
+	r, err := od.Revenue()
+	assert.Nil(t, err)
+	assert.Equal(t, float64(0), r.DeliveryFee)
+	assert.Equal(t, float64(95), r.Net)
+}
+
+func TestOrderDetails_Revenue_InvalidValue(t *testing.T) {
+	od := OrderDetails{Reference: "ref", Subtotal: "10", Benefits: []Benefit{
+		{SponsorshipValues: []Sponsorship{{Name: SponsorIfood, Value: "dez"}}},
+	}}
+	_, err := od.Revenue()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "dez")
+}
+
+func TestPayment_Change(t *testing.T) {
+	od := OrderDetails{}
+	assert.Nil(t, json.Unmarshal([]byte(benefitsDetails), &od))
+	change, err := od.Payments[0].Change()
+	assert.Nil(t, err)
+	assert.Equal(t, float64(7), change)
+	change, err = Payment{Value: "10"}.Change()
+	assert.Nil(t, err)
+	assert.Equal(t, float64(0), change)
+	_, err = Payment{Value: "10", ChangeFor: "x"}.Change()
+	assert.NotNil(t, err)
+}
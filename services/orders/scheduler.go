@@ -0,0 +1,348 @@
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/kpango/glg"
+)
+
+const (
+	// OrderTimingImmediate order to be prepared right away
+	OrderTimingImmediate = "IMMEDIATE"
+	// OrderTimingScheduled order to be delivered in a future window
+	OrderTimingScheduled = "SCHEDULED"
+)
+
+var (
+	// ErrOrderNotScheduled the order has no schedule
+	ErrOrderNotScheduled = errors.New("Order is not scheduled")
+
+	orderTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05"}
+)
+
+type (
+	// ScheduledOrder is a scheduled order held until its preparation start,
+	// keyed by the order ID that events carry as CorrelationID
+	ScheduledOrder struct {
+		ID               string       `json:"id"`
+		Reference        string       `json:"reference"`
+		PreparationStart time.Time    `json:"preparationStart"`
+		Order            OrderDetails `json:"order"`
+	}
+
+	// ScheduleStore persists the scheduler queue so it survives restarts
+	ScheduleStore interface {
+		Save(so ScheduledOrder) error
+		Delete(orderID string) error
+		List() ([]ScheduledOrder, error)
+	}
+
+	// Scheduler holds scheduled orders and calls back at their preparation start
+	Scheduler struct {
+		store    ScheduleStore
+		callback func(ScheduledOrder)
+		now      func() time.Time
+		mu       sync.Mutex
+		queue    map[string]ScheduledOrder
+	}
+
+	memoryScheduleStore struct {
+		mu     sync.Mutex
+		orders map[string]ScheduledOrder
+	}
+
+	fileScheduleStore struct {
+		mu   sync.Mutex
+		path string
+	}
+)
+
+// IsScheduled reports if the order should be held until its preparation start
+func (od OrderDetails) IsScheduled() bool {
+	return (od.OrderTiming == OrderTimingScheduled) || (od.Schedule != nil)
+}
+
+// PreparationTime parses Preparationtimeinseconds
+func (od OrderDetails) PreparationTime() (d time.Duration, err error) {
+	seconds, err := strconv.Atoi(strings.TrimSpace(od.Preparationtimeinseconds))
+	if err != nil {
+		err = fmt.Errorf("Order '%s' preparation time '%s' is invalid", od.Reference, od.Preparationtimeinseconds)
+		return
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// PreparationStart returns when a scheduled order should start being prepared,
+// the start of the delivery window minus the preparation time
+func (od OrderDetails) PreparationStart() (start time.Time, err error) {
+	if !od.IsScheduled() {
+		err = ErrOrderNotScheduled
+		return
+	}
+	deliveryStart := od.Deliverydatetime
+	if od.Schedule != nil {
+		deliveryStart = od.Schedule.DeliveryDateTimeStart
+	}
+	if start, err = parseOrderTime(deliveryStart); err != nil {
+		return
+	}
+	if preparation, err := od.PreparationTime(); err == nil {
+		start = start.Add(-preparation)
+	}
+	return start, nil
+}
+
+// NewScheduler returns a scheduler with the orders previously saved in the store
+func NewScheduler(store ScheduleStore, callback func(ScheduledOrder)) (s *Scheduler, err error) {
+	if store == nil {
+		store = NewMemoryScheduleStore()
+	}
+	saved, err := store.List()
+	if err != nil {
+		glg.Error("[SDK] Orders NewScheduler store.List: ", err.Error())
+		return
+	}
+	s = &Scheduler{
+		store:    store,
+		callback: callback,
+		now:      time.Now,
+		queue:    make(map[string]ScheduledOrder),
+	}
+	for _, so := range saved {
+		s.queue[so.ID] = so
+	}
+	return
+}
+
+// Schedule queues a scheduled order until its preparation start
+func (s *Scheduler) Schedule(od OrderDetails) (err error) {
+	if od.ID == "" {
+		return ErrOrderIDNotSpecified
+	}
+	start, err := od.PreparationStart()
+	if err != nil {
+		glg.Error("[SDK] Orders Scheduler Schedule PreparationStart: ", err.Error())
+		return
+	}
+	so := ScheduledOrder{ID: od.ID, Reference: od.Reference, PreparationStart: start, Order: od}
+	if err = s.store.Save(so); err != nil {
+		glg.Error("[SDK] Orders Scheduler Schedule store.Save: ", err.Error())
+		return
+	}
+	s.mu.Lock()
+	s.queue[od.ID] = so
+	s.mu.Unlock()
+	return
+}
+
+// Cancel removes an order from the queue without calling back
+func (s *Scheduler) Cancel(orderID string) (err error) {
+	s.mu.Lock()
+	delete(s.queue, orderID)
+	s.mu.Unlock()
+	return s.store.Delete(orderID)
+}
+
+// Pending returns the queued orders sorted by preparation start
+func (s *Scheduler) Pending() (pending []ScheduledOrder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, so := range s.queue {
+		pending = append(pending, so)
+	}
+	sortScheduled(pending)
+	return
+}
+
+// HandleEvent fires the order on RECOMMENDED_PREPARATION_START
+// and forgets it when the order gets cancelled
+func (s *Scheduler) HandleEvent(event events.Event) (err error) {
+	switch event.Name() {
+	case "RECOMMENDED_PREPARATION_START":
+		s.mu.Lock()
+		so, ok := s.queue[event.CorrelationID]
+		delete(s.queue, event.CorrelationID)
+		s.mu.Unlock()
+		if ok {
+			return s.fire(so)
+		}
+	case "CANCELLED":
+		return s.Cancel(event.CorrelationID)
+	}
+	return
+}
+
+// Fire calls back every order whose preparation start has passed
+func (s *Scheduler) Fire() (err error) {
+	now := s.now()
+	var due []ScheduledOrder
+	s.mu.Lock()
+	for id, so := range s.queue {
+		if !so.PreparationStart.After(now) {
+			due = append(due, so)
+			delete(s.queue, id)
+		}
+	}
+	s.mu.Unlock()
+	sortScheduled(due)
+	for _, so := range due {
+		if fireErr := s.fire(so); fireErr != nil {
+			err = fireErr
+		}
+	}
+	return
+}
+
+// Run calls Fire every interval until stop is closed
+func (s *Scheduler) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.Fire()
+		}
+	}
+}
+
+func (s *Scheduler) fire(so ScheduledOrder) (err error) {
+	if s.callback != nil {
+		s.callback(so)
+	}
+	if err = s.store.Delete(so.ID); err != nil {
+		glg.Error("[SDK] Orders Scheduler store.Delete: ", err.Error())
+	}
+	return
+}
+
+// NewMemoryScheduleStore returns a store that does not survive restarts
+func NewMemoryScheduleStore() ScheduleStore {
+	return &memoryScheduleStore{orders: make(map[string]ScheduledOrder)}
+}
+
+func (m *memoryScheduleStore) Save(so ScheduledOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orders[so.ID] = so
+	return nil
+}
+
+func (m *memoryScheduleStore) Delete(orderID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.orders, orderID)
+	return nil
+}
+
+func (m *memoryScheduleStore) List() (l []ScheduledOrder, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, so := range m.orders {
+		l = append(l, so)
+	}
+	sortScheduled(l)
+	return
+}
+
+// NewFileScheduleStore returns a store that keeps the queue in a JSON file
+func NewFileScheduleStore(path string) ScheduleStore {
+	return &fileScheduleStore{path: path}
+}
+
+func (f *fileScheduleStore) Save(so ScheduledOrder) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orders, err := f.read()
+	if err != nil {
+		return err
+	}
+	orders[so.ID] = so
+	return f.write(orders)
+}
+
+func (f *fileScheduleStore) Delete(orderID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orders, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := orders[orderID]; !ok {
+		return nil
+	}
+	delete(orders, orderID)
+	return f.write(orders)
+}
+
+func (f *fileScheduleStore) List() (l []ScheduledOrder, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orders, err := f.read()
+	if err != nil {
+		return
+	}
+	for _, so := range orders {
+		l = append(l, so)
+	}
+	sortScheduled(l)
+	return
+}
+
+func (f *fileScheduleStore) read() (orders map[string]ScheduledOrder, err error) {
+	orders = make(map[string]ScheduledOrder)
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return orders, nil
+	}
+	if err != nil {
+		return
+	}
+	if len(data) == 0 {
+		return
+	}
+	return orders, json.Unmarshal(data, &orders)
+}
+
+// write replaces the file atomically so a crash never leaves it half written
+func (f *fileScheduleStore) write(orders map[string]ScheduledOrder) (err error) {
+	data, err := json.Marshal(orders)
+	if err != nil {
+		return
+	}
+	tmp := f.path + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func sortScheduled(l []ScheduledOrder) {
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].PreparationStart.Equal(l[j].PreparationStart) {
+			return l[i].ID < l[j].ID
+		}
+		return l[i].PreparationStart.Before(l[j].PreparationStart)
+	})
+}
+
+func parseOrderTime(value string) (t time.Time, err error) {
+	for _, layout := range orderTimeLayouts {
+		if t, err = time.Parse(layout, value); err == nil {
+			return
+		}
+	}
+	err = fmt.Errorf("time '%s' is invalid, should be RFC3339", value)
+	return
+}
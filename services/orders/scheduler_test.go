@@ -0,0 +1,116 @@
+package orders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+)
+
+// scheduledOrder has a reference different from its id, as the API sends
+func scheduledOrder(id, start string) OrderDetails {
+	return OrderDetails{
+		ID:                       id,
+		Reference:                "ref-" + id,
+		OrderTiming:              OrderTimingScheduled,
+		Preparationtimeinseconds: "1800",
+		Schedule: &Schedule{
+			DeliveryDateTimeStart: start,
+			DeliveryDateTimeEnd:   start,
+		},
+	}
+}
+
+func TestOrderDetails_PreparationStart(t *testing.T) {
+	od := scheduledOrder("ref", "2021-03-01T20:00:00Z")
+	start, err := od.PreparationStart()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 19, 30, 0, 0, time.UTC), start)
+	od.Preparationtimeinseconds = ""
+	start, err = od.PreparationStart()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 20, 0, 0, 0, time.UTC), start)
+}
+
+func TestOrderDetails_PreparationStart_NotScheduled(t *testing.T) {
+	_, err := OrderDetails{OrderTiming: OrderTimingImmediate}.PreparationStart()
+	assert.Equal(t, ErrOrderNotScheduled, err)
+}
+
+func TestOrderDetails_PreparationStart_InvalidTime(t *testing.T) {
+	_, err := scheduledOrder("ref", "amanhã").PreparationStart()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "amanhã")
+}
+
+func TestScheduler_Fire(t *testing.T) {
+	var fired []string
+	s, err := NewScheduler(nil, func(so ScheduledOrder) { fired = append(fired, so.ID) })
+	assert.Nil(t, err)
+	s.now = func() time.Time { return time.Date(2021, 3, 1, 19, 45, 0, 0, time.UTC) }
+	assert.Nil(t, s.Schedule(scheduledOrder("late", "2021-03-01T20:00:00Z")))
+	assert.Nil(t, s.Schedule(scheduledOrder("later", "2021-03-01T21:00:00Z")))
+	assert.Nil(t, s.Schedule(scheduledOrder("early", "2021-03-01T19:00:00Z")))
+	assert.Equal(t, ErrOrderNotScheduled, s.Schedule(OrderDetails{ID: "now"}))
+	assert.Nil(t, s.Fire())
+	assert.Equal(t, []string{"early", "late"}, fired)
+	pending := s.Pending()
+	assert.Equal(t, 1, len(pending))
+	assert.Equal(t, "later", pending[0].ID)
+	assert.Equal(t, "ref-later", pending[0].Reference)
+	assert.Equal(t, ErrOrderIDNotSpecified, s.Schedule(OrderDetails{Reference: "ref-later"}))
+}
+
+func TestScheduler_HandleEvent(t *testing.T) {
+	var fired []string
+	s, err := NewScheduler(nil, func(so ScheduledOrder) { fired = append(fired, so.ID) })
+	assert.Nil(t, err)
+	assert.Nil(t, s.Schedule(scheduledOrder("a", "2999-03-01T20:00:00Z")))
+	assert.Nil(t, s.Schedule(scheduledOrder("b", "2999-03-01T20:00:00Z")))
+	// events carry the order id, never the reference
+	assert.Nil(t, s.HandleEvent(events.Event{Code: "RPS", CorrelationID: "ref-a"}))
+	assert.Equal(t, 0, len(fired))
+	assert.Nil(t, s.HandleEvent(events.Event{Code: "RPS", CorrelationID: "a"}))
+	assert.Nil(t, s.HandleEvent(events.Event{Code: "CANCELLED", CorrelationID: "b"}))
+	assert.Nil(t, s.HandleEvent(events.Event{Code: "RPS", CorrelationID: "b"}))
+	assert.Equal(t, []string{"a"}, fired)
+	assert.Equal(t, 0, len(s.Pending()))
+}
+
+func TestScheduler_FileStoreSurvivesRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scheduler")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "queue.json")
+	s, err := NewScheduler(NewFileScheduleStore(path), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, s.Schedule(scheduledOrder("a", "2021-03-01T20:00:00Z")))
+	assert.Nil(t, s.Schedule(scheduledOrder("b", "2021-03-01T21:00:00Z")))
+	assert.Nil(t, s.Cancel("b"))
+
+	var fired []ScheduledOrder
+	restarted, err := NewScheduler(NewFileScheduleStore(path), func(so ScheduledOrder) { fired = append(fired, so) })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(restarted.Pending()))
+	assert.Nil(t, restarted.Fire())
+	assert.Equal(t, 1, len(fired))
+	assert.Equal(t, "a", fired[0].Order.ID)
+	assert.Equal(t, "ref-a", fired[0].Reference)
+	saved, err := NewFileScheduleStore(path).List()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(saved))
+}
+
+func TestScheduler_FileStoreCorrupted(t *testing.T) {
+	f, err := ioutil.TempFile("", "scheduler")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	f.WriteString("{")
+	f.Close()
+	_, err = NewScheduler(NewFileScheduleStore(f.Name()), nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,447 @@
+package orders
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// TicketText renders the ticket as plain text
+	TicketText TicketFormat = iota
+	// TicketESCPOS renders the ticket as ESC/POS printer commands
+	TicketESCPOS
+	// TicketHTML renders the ticket as an HTML document
+	TicketHTML
+)
+
+const (
+	// Paper58mm thermal paper, 32 columns
+	Paper58mm PaperWidth = 58
+	// Paper80mm thermal paper, 48 columns
+	Paper80mm PaperWidth = 80
+)
+
+var (
+	escposInit     = []byte{0x1b, 0x40}
+	escposCodepage = []byte{0x1b, 0x74, 0x03} // CP860, portuguese
+	escposFeedCut  = []byte{0x1b, 0x64, 0x04, 0x1d, 0x56, 0x42, 0x00}
+
+	// cp860 maps the portuguese accented runes to the printer codepage
+	cp860 = map[rune]byte{
+		'Ç': 0x80, 'ü': 0x81, 'é': 0x82, 'â': 0x83, 'ã': 0x84, 'à': 0x85, 'Á': 0x86, 'ç': 0x87,
+		'ê': 0x88, 'Ê': 0x89, 'è': 0x8a, 'Í': 0x8b, 'Ô': 0x8c, 'ì': 0x8d, 'Ã': 0x8e, 'Â': 0x8f,
+		'É': 0x90, 'À': 0x91, 'È': 0x92, 'ô': 0x93, 'õ': 0x94, 'ò': 0x95, 'Ú': 0x96, 'ù': 0x97,
+		'Ì': 0x98, 'Õ': 0x99, 'Ü': 0x9a, 'Ù': 0x9d, 'Ó': 0x9f, 'á': 0xa0, 'í': 0xa1, 'ó': 0xa2,
+		'ú': 0xa3, 'ñ': 0xa4, 'Ñ': 0xa5, 'ª': 0xa6, 'º': 0xa7, 'Ò': 0xa9,
+	}
+)
+
+const defaultTextTicket = `{{center .Merchant}}
+{{center (printf "PEDIDO #%s" .ShortReference)}}
+{{row .Type .CreatedAt}}
+{{- if .PickupCode}}
+{{center (printf "CODIGO DE RETIRADA: %s" .PickupCode)}}
+{{- end}}
+{{- if .Table}}
+{{center (printf "MESA %s" .Table)}}
+{{- end}}
+{{divider}}
+{{- range .Items}}
+{{row (printf "%sx %s" .Quantity .Name) .Total}}
+{{- range .Subitems}}
+{{row (printf "  + %sx %s" .Quantity .Name) .Total}}
+{{- end}}
+{{- if .Observations}}
+{{wrap (printf "  OBS: %s" .Observations)}}
+{{- end}}
+{{- end}}
+{{divider}}
+{{row "Subtotal" .Subtotal}}
+{{row "Taxa de entrega" .DeliveryFee}}
+{{row "TOTAL" .Total}}
+{{- range .Payments}}
+{{row .Name .Value}}
+{{- if .ChangeFor}}
+{{row "  Troco para" .ChangeFor}}
+{{- end}}
+{{- end}}
+{{divider}}
+{{wrap .Customer.Name}}
+{{- if .Customer.Phone}}
+{{wrap (printf "Tel: %s" .Customer.Phone)}}
+{{- end}}
+{{- if .Address}}
+{{wrap .Address}}
+{{- end}}
+{{- if .AddressReference}}
+{{wrap (printf "Ref: %s" .AddressReference)}}
+{{- end}}
+`
+
+const defaultHTMLTicket = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Pedido #{{.ShortReference}}</title></head>
+<body style="font-family: monospace; max-width: {{.Columns}}ch">
+<h1>{{.Merchant}}</h1>
+<h2>Pedido #{{.ShortReference}}</h2>
+<p>{{.Type}} - {{.CreatedAt}}</p>
+{{- if .PickupCode}}
+<p>Código de retirada: <strong>{{.PickupCode}}</strong></p>
+{{- end}}
+{{- if .Table}}
+<p>Mesa <strong>{{.Table}}</strong></p>
+{{- end}}
+<table>
+{{- range .Items}}
+<tr><td>{{.Quantity}}x {{.Name}}</td><td>{{.Total}}</td></tr>
+{{- range .Subitems}}
+<tr><td>&nbsp;&nbsp;+ {{.Quantity}}x {{.Name}}</td><td>{{.Total}}</td></tr>
+{{- end}}
+{{- if .Observations}}
+<tr><td colspan="2"><em>OBS: {{.Observations}}</em></td></tr>
+{{- end}}
+{{- end}}
+</table>
+<table>
+<tr><td>Subtotal</td><td>{{.Subtotal}}</td></tr>
+<tr><td>Taxa de entrega</td><td>{{.DeliveryFee}}</td></tr>
+<tr><td><strong>TOTAL</strong></td><td><strong>{{.Total}}</strong></td></tr>
+{{- range .Payments}}
+<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- if .ChangeFor}}
+<tr><td>&nbsp;&nbsp;Troco para</td><td>{{.ChangeFor}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+<p>{{.Customer.Name}}{{if .Customer.Phone}}<br>Tel: {{.Customer.Phone}}{{end}}</p>
+{{- if .Address}}
+<p>{{.Address}}{{if .AddressReference}}<br>Ref: {{.AddressReference}}{{end}}</p>
+{{- end}}
+</body>
+</html>
+`
+
+type (
+	// TicketFormat output format of a kitchen ticket
+	TicketFormat int
+
+	// PaperWidth thermal paper width in millimeters
+	PaperWidth int
+
+	// TicketConfig configures a TicketRenderer
+	//
+	// TextTemplate is used by the TicketText and TicketESCPOS formats,
+	// HTMLTemplate by TicketHTML. Both receive a Ticket and fall back
+	// to the default layouts when empty. Text templates can use the
+	// width aware funcs: center, right, row, wrap and divider.
+	TicketConfig struct {
+		Paper        PaperWidth
+		TextTemplate string
+		HTMLTemplate string
+	}
+
+	// TicketRenderer builds kitchen tickets from order details
+	TicketRenderer struct {
+		columns int
+		text    *template.Template
+		html    *htmltemplate.Template
+	}
+
+	// Ticket is the data handed to the ticket templates,
+	// all money values are already formatted as BRL
+	Ticket struct {
+		Columns          int
+		Merchant         string
+		Reference        string
+		ShortReference   string
+		CreatedAt        string
+		Type             string
+		PickupCode       string
+		Table            string
+		Items            []TicketItem
+		Subtotal         string
+		DeliveryFee      string
+		Total            string
+		Payments         []TicketPayment
+		Customer         Customer
+		Address          string
+		AddressReference string
+	}
+
+	// TicketItem item line of a ticket
+	TicketItem struct {
+		Name         string
+		Quantity     string
+		Total        string
+		Observations string
+		Subitems     []TicketItem
+	}
+
+	// TicketPayment payment line of a ticket
+	TicketPayment struct {
+		Name      string
+		Value     string
+		ChangeFor string
+	}
+)
+
+// Columns returns how many characters fit in a line of the paper
+func (p PaperWidth) Columns() int {
+	if p == Paper58mm {
+		return 32
+	}
+	return 48
+}
+
+// NewTicketRenderer returns a renderer for the given config
+func NewTicketRenderer(cfg TicketConfig) (tr *TicketRenderer, err error) {
+	if cfg.Paper == 0 {
+		cfg.Paper = Paper80mm
+	}
+	if (cfg.Paper != Paper58mm) && (cfg.Paper != Paper80mm) {
+		err = fmt.Errorf("paper width '%dmm' is invalid, should be 58 or 80", cfg.Paper)
+		return
+	}
+	if cfg.TextTemplate == "" {
+		cfg.TextTemplate = defaultTextTicket
+	}
+	if cfg.HTMLTemplate == "" {
+		cfg.HTMLTemplate = defaultHTMLTicket
+	}
+	tr = &TicketRenderer{columns: cfg.Paper.Columns()}
+	tr.text, err = template.New("ticket").Funcs(tr.funcs()).Parse(cfg.TextTemplate)
+	if err != nil {
+		err = fmt.Errorf("could not parse ticket text template: %s", err.Error())
+		return nil, err
+	}
+	tr.html, err = htmltemplate.New("ticket").Parse(cfg.HTMLTemplate)
+	if err != nil {
+		err = fmt.Errorf("could not parse ticket html template: %s", err.Error())
+		return nil, err
+	}
+	return
+}
+
+// Render builds the ticket of an order in the given format
+func (tr *TicketRenderer) Render(od OrderDetails, format TicketFormat) (b []byte, err error) {
+	ticket := tr.NewTicket(od)
+	buf := &bytes.Buffer{}
+	switch format {
+	case TicketText:
+		err = tr.text.Execute(buf, ticket)
+	case TicketESCPOS:
+		if err = tr.text.Execute(buf, ticket); err == nil {
+			return escpos(buf.String()), nil
+		}
+	case TicketHTML:
+		err = tr.html.Execute(buf, ticket)
+	default:
+		err = fmt.Errorf("ticket format '%d' is invalid", format)
+	}
+	if err != nil {
+		return
+	}
+	return buf.Bytes(), nil
+}
+
+// NewTicket maps the order details into the template data
+func (tr *TicketRenderer) NewTicket(od OrderDetails) (t Ticket) {
+	t = Ticket{
+		Columns:          tr.columns,
+		Merchant:         od.Merchant.Name,
+		Reference:        od.Reference,
+		ShortReference:   od.Shortreference,
+		CreatedAt:        od.Createdat,
+		Type:             string(od.Type),
+		PickupCode:       od.PickupCode(),
+		Subtotal:         formatAmount(od.Subtotal),
+		DeliveryFee:      formatAmount(od.Deliveryfee),
+		Total:            formatAmount(od.Totalprice),
+		Customer:         od.Customer,
+		Address:          od.Deliveryaddress.Formattedaddress,
+		AddressReference: od.Deliveryaddress.Reference,
+	}
+	if od.Indoor != nil {
+		t.Table = od.Indoor.Table
+	}
+	if (t.Address != "") && (od.Deliveryaddress.Complement != "") {
+		t.Address = fmt.Sprintf("%s - %s", t.Address, od.Deliveryaddress.Complement)
+	}
+	for _, item := range od.Items {
+		ti := TicketItem{
+			Name:         item.Name,
+			Quantity:     item.Quantity,
+			Total:        formatAmount(item.Totalprice),
+			Observations: item.Observations,
+		}
+		for _, sub := range item.Subitems {
+			ti.Subitems = append(ti.Subitems, TicketItem{
+				Name:     sub.Name,
+				Quantity: sub.Quantity,
+				Total:    formatAmount(sub.Totalprice),
+			})
+		}
+		t.Items = append(t.Items, ti)
+	}
+	for _, p := range od.Payments {
+		t.Payments = append(t.Payments, TicketPayment{
+			Name:      p.Name,
+			Value:     formatAmount(p.Value),
+			ChangeFor: formatAmount(p.ChangeFor),
+		})
+	}
+	return
+}
+
+func (tr *TicketRenderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"brl":     formatAmount,
+		"divider": func() string { return strings.Repeat("-", tr.columns) },
+		"center":  func(s string) string { return alignLines(s, tr.columns, true) },
+		"right":   func(s string) string { return alignLines(s, tr.columns, false) },
+		"wrap":    func(s string) string { return strings.Join(wrapText(s, tr.columns), "\n") },
+		"row":     func(left, right string) string { return row(left, right, tr.columns) },
+	}
+}
+
+// FormatBRL formats a value as brazilian currency, e.g. R$ 1.234,50
+func FormatBRL(value float64) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	cents := int64(math.Round(value * 100))
+	return fmt.Sprintf("%sR$ %s,%02d", sign, groupThousands(cents/100, "."), cents%100)
+}
+
+// ParseAmount parses the monetary strings returned by the orders API
+func ParseAmount(amount string) (float64, error) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(amount, 64)
+}
+
+// formatAmount formats API amounts, values that are not numbers are kept as is
+func formatAmount(amount string) string {
+	if amount == "" {
+		return ""
+	}
+	value, err := ParseAmount(amount)
+	if err != nil {
+		return amount
+	}
+	return FormatBRL(value)
+}
+
+func groupThousands(n int64, sep string) string {
+	digits := strconv.FormatInt(n, 10)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + sep + digits[i:]
+	}
+	return digits
+}
+
+func row(left, right string, columns int) string {
+	rightLen := utf8.RuneCountInString(right)
+	lines := wrapText(left, columns)
+	last := lines[len(lines)-1]
+	gap := columns - utf8.RuneCountInString(last) - rightLen
+	if gap < 1 {
+		lines = append(lines, strings.Repeat(" ", maxInt(columns-rightLen, 0))+right)
+		return strings.Join(lines, "\n")
+	}
+	lines[len(lines)-1] = last + strings.Repeat(" ", gap) + right
+	return strings.Join(lines, "\n")
+}
+
+func alignLines(s string, columns int, center bool) string {
+	lines := wrapText(s, columns)
+	for i, line := range lines {
+		gap := columns - utf8.RuneCountInString(line)
+		if center {
+			gap = gap / 2
+		}
+		lines[i] = strings.Repeat(" ", gap) + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapText breaks a text into lines of at most columns runes,
+// keeping the leading indentation on every line
+func wrapText(s string, columns int) (lines []string) {
+	indent := s[:len(s)-len(strings.TrimLeft(s, " "))]
+	if len(indent) >= columns {
+		indent = ""
+	}
+	defer func() {
+		for i := range lines {
+			lines[i] = indent + lines[i]
+		}
+	}()
+	columns -= len(indent)
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for utf8.RuneCountInString(word) > columns {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:columns]))
+			word = string(runes[columns:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= columns:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
+
+// escpos encodes the text for the printer, control characters other
+// than line breaks are dropped so order fields can not inject commands
+func escpos(text string) []byte {
+	buf := &bytes.Buffer{}
+	buf.Write(escposInit)
+	buf.Write(escposCodepage)
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			buf.WriteByte('\n')
+		case unicode.IsControl(r):
+			continue
+		case r < utf8.RuneSelf:
+			buf.WriteByte(byte(r))
+		default:
+			if b, ok := cp860[r]; ok {
+				buf.WriteByte(b)
+				continue
+			}
+			buf.WriteByte('?')
+		}
+	}
+	buf.Write(escposFeedCut)
+	return buf.Bytes()
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
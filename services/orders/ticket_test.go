@@ -0,0 +1,133 @@
+package orders
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/merchant"
+	"github.com/stretchr/testify/assert"
+)
+
+var ticketOrder = OrderDetails{
+	Reference:      "1234567890",
+	Shortreference: "7788",
+	Createdat:      "2021-03-01T19:30:00Z",
+	Type:           "DELIVERY",
+	Merchant:       merchant.Merchant{Name: "Pizzaria"},
+	Payments: []Payment{
+		{Name: "DINHEIRO", Value: "1234.5", ChangeFor: "1300"},
+	},
+	Customer: Customer{Name: "João", Phone: "0800 123"},
+	Items: []Item{
+		{
+			Name:         "Pizza grande de calabresa com borda recheada",
+			Quantity:     "1",
+			Totalprice:   "1224.5",
+			Observations: "sem cebola",
+			Subitems: []Subitem{
+				{Name: "Borda de catupiry", Quantity: "1", Totalprice: "5"},
+			},
+		},
+	},
+	Subtotal:    "1224.5",
+	Deliveryfee: "10",
+	Totalprice:  "1234.5",
+	Deliveryaddress: DeliveryAddress{
+		Formattedaddress: "Rua A, 10",
+		Complement:       "apto 2",
+		Reference:        "perto da praça",
+	},
+}
+
+func TestFormatBRL(t *testing.T) {
+	assert.Equal(t, "R$ 0,00", FormatBRL(0))
+	assert.Equal(t, "R$ 10,50", FormatBRL(10.5))
+	assert.Equal(t, "R$ 1.234.567,89", FormatBRL(1234567.89))
+	assert.Equal(t, "-R$ 3,10", FormatBRL(-3.1))
+}
+
+func TestNewTicketRenderer_InvalidPaper(t *testing.T) {
+	_, err := NewTicketRenderer(TicketConfig{Paper: 70})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "70mm")
+}
+
+func TestNewTicketRenderer_InvalidTemplate(t *testing.T) {
+	_, err := NewTicketRenderer(TicketConfig{TextTemplate: "{{.Merchant"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "text template")
+}
+
+func TestRender_Text58mm(t *testing.T) {
+	tr, err := NewTicketRenderer(TicketConfig{Paper: Paper58mm})
+	assert.Nil(t, err)
+	b, err := tr.Render(ticketOrder, TicketText)
+	assert.Nil(t, err)
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		assert.LessOrEqual(t, len([]rune(line)), 32, line)
+	}
+	ticket := string(b)
+	assert.Contains(t, ticket, "PEDIDO #7788")
+	assert.Contains(t, ticket, "R$ 1.224,50")
+	assert.Contains(t, ticket, "  + 1x Borda de catupiry")
+	assert.Contains(t, ticket, "OBS: sem cebola")
+	assert.Contains(t, ticket, "Rua A, 10 - apto 2")
+	assert.Contains(t, ticket, "Ref: perto da praça")
+	assert.Contains(t, ticket, "  Troco para         R$ 1.300,00")
+}
+
+func TestRender_TextRow(t *testing.T) {
+	tr, err := NewTicketRenderer(TicketConfig{Paper: Paper80mm, TextTemplate: `{{row "TOTAL" .Total}}`})
+	assert.Nil(t, err)
+	b, err := tr.Render(ticketOrder, TicketText)
+	assert.Nil(t, err)
+	assert.Equal(t, "TOTAL"+strings.Repeat(" ", 32)+"R$ 1.234,50", string(b))
+}
+
+func TestRender_ESCPOS(t *testing.T) {
+	tr, err := NewTicketRenderer(TicketConfig{TextTemplate: "{{.Customer.Name}}"})
+	assert.Nil(t, err)
+	b, err := tr.Render(ticketOrder, TicketESCPOS)
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(b, append(escposInit, escposCodepage...)))
+	assert.True(t, bytes.HasSuffix(b, escposFeedCut))
+	assert.True(t, bytes.Contains(b, []byte{'J', 'o', 0x84, 'o'}))
+
+	od := ticketOrder
+	od.Customer.Name = "Jo\x1bd\x1dVA\tN\x7f\u0085\nB"
+	b, err = tr.Render(od, TicketESCPOS)
+	assert.Nil(t, err)
+	body := bytes.TrimSuffix(bytes.TrimPrefix(b, append(escposInit, escposCodepage...)), escposFeedCut)
+	assert.Equal(t, "JodVAN\nB", string(body))
+}
+
+func TestRender_HTML(t *testing.T) {
+	od := ticketOrder
+	od.Items = append([]Item{}, od.Items...)
+	od.Items[0].Observations = "<b>sem cebola</b>"
+	tr, err := NewTicketRenderer(TicketConfig{})
+	assert.Nil(t, err)
+	b, err := tr.Render(od, TicketHTML)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "<h2>Pedido #7788</h2>")
+	assert.Contains(t, string(b), "&lt;b&gt;sem cebola&lt;/b&gt;")
+}
+
+func TestRender_InvalidFormat(t *testing.T) {
+	tr, err := NewTicketRenderer(TicketConfig{})
+	assert.Nil(t, err)
+	_, err = tr.Render(ticketOrder, TicketFormat(9))
+	assert.NotNil(t, err)
+}
+
+func TestRender_FromAPIDetails(t *testing.T) {
+	od := OrderDetails{}
+	assert.Nil(t, json.Unmarshal([]byte(orderDetails), &od))
+	tr, err := NewTicketRenderer(TicketConfig{Paper: Paper58mm})
+	assert.Nil(t, err)
+	b, err := tr.Render(od, TicketText)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "Preço total")
+}
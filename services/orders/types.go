@@ -13,7 +13,7 @@ type (
 		Reference                string            `json:"reference"`
 		Shortreference           string            `json:"shortReference"`
 		Createdat                string            `json:"createdAt"`
-		Type                     string            `json:"type"`
+		Type                     OrderType         `json:"type"`
 		Merchant                 merchant.Merchant `json:"merchant"`
 		Payments                 []Payment         `json:"payments"`
 		Customer                 Customer          `json:"customer"`
@@ -24,6 +24,62 @@ type (
 		Deliveryaddress          DeliveryAddress   `json:"deliveryAddress"`
 		Deliverydatetime         string            `json:"deliveryDateTime"`
 		Preparationtimeinseconds string            `json:"preparationTimeInSeconds"`
+		OrderTiming              string            `json:"orderTiming,omitempty"`
+		Schedule                 *Schedule         `json:"schedule,omitempty"`
+		Delivery                 *Delivery         `json:"delivery,omitempty"`
+		Takeout                  *Takeout          `json:"takeout,omitempty"`
+		Indoor                   *Indoor           `json:"indoor,omitempty"`
+		Benefits                 []Benefit         `json:"benefits,omitempty"`
+		AdditionalFees           []AdditionalFee   `json:"additionalFees,omitempty"`
+	}
+
+	// Benefit discount applied to an order and who pays for it
+	Benefit struct {
+		Value             string        `json:"value"`
+		Target            string        `json:"target"`
+		TargetID          string        `json:"targetId,omitempty"`
+		SponsorshipValues []Sponsorship `json:"sponsorshipValues"`
+	}
+
+	// Sponsorship share of a benefit paid by a sponsor (IFOOD, MERCHANT)
+	Sponsorship struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	// AdditionalFee charged to the customer besides the delivery fee
+	AdditionalFee struct {
+		Type        string `json:"type"`
+		Value       string `json:"value"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Delivery specifics of a DELIVERY order
+	Delivery struct {
+		Mode             string `json:"mode"`
+		DeliveredBy      string `json:"deliveredBy"`
+		DeliveryDateTime string `json:"deliveryDateTime"`
+		PickupCode       string `json:"pickupCode,omitempty"`
+	}
+
+	// Takeout specifics of a TAKEOUT order
+	Takeout struct {
+		Mode            string `json:"mode"`
+		TakeoutDateTime string `json:"takeoutDateTime"`
+		PickupCode      string `json:"pickupCode"`
+	}
+
+	// Indoor specifics of an INDOOR order
+	Indoor struct {
+		Mode           string `json:"mode"`
+		Table          string `json:"table"`
+		IndoorDateTime string `json:"indoorDateTime"`
+	}
+
+	// Schedule delivery window of a scheduled order
+	Schedule struct {
+		DeliveryDateTimeStart string `json:"deliveryDateTimeStart"`
+		DeliveryDateTimeEnd   string `json:"deliveryDateTimeEnd"`
 	}
 
 	// Payment details
@@ -34,6 +90,7 @@ type (
 		Prepaid   string `json:"prepaid"`
 		Issuer    string `json:"issuer"`
 		Collector string `json:"collector,omitempty"`
+		ChangeFor string `json:"changeFor,omitempty"`
 	}
 
 	// Customer details
@@ -119,6 +176,65 @@ type (
 		Longitude          float64     `json:"longitude"`
 		Eta                int         `json:"eta"`
 	}
+
+	// DriverAvailability API response of an iFood driver quote
+	DriverAvailability struct {
+		ID              string      `json:"id"`
+		ExpirationAt    time.Time   `json:"expirationAt"`
+		CreatedAt       time.Time   `json:"createdAt"`
+		Distance        int         `json:"distance"`
+		PreparationTime int         `json:"preparationTime"`
+		Quote           DriverQuote `json:"quote"`
+		DeliveryTime    struct {
+			Min int `json:"min"`
+			Max int `json:"max"`
+		} `json:"deliveryTime"`
+		HasPaymentMethods bool `json:"hasPaymentMethods"`
+	}
+
+	// DriverQuote fee charged to the merchant for an iFood driver
+	DriverQuote struct {
+		GrossValue float64 `json:"grossValue"`
+		Discount   float64 `json:"discount"`
+		Raise      float64 `json:"raise"`
+		NetValue   float64 `json:"netValue"`
+	}
+
+	// OrderUpdate change of items or address proposed on UPDATE_REQUESTED,
+	// fields left empty are not changed
+	OrderUpdate struct {
+		ID              string           `json:"id"`
+		Reason          string           `json:"reason,omitempty"`
+		Items           []Item           `json:"items,omitempty"`
+		DeliveryAddress *DeliveryAddress `json:"deliveryAddress,omitempty"`
+		Subtotal        string           `json:"subTotal,omitempty"`
+		Deliveryfee     string           `json:"deliveryFee,omitempty"`
+		Totalprice      string           `json:"totalPrice,omitempty"`
+		ExpiresAt       string           `json:"expiresAt,omitempty"`
+	}
+
+	preparationTimeIncrease struct {
+		Seconds int `json:"additionalTimeInSeconds"`
+	}
+
+	delayNotification struct {
+		Seconds int `json:"delayInSeconds"`
+	}
+
+	driverRequest struct {
+		QuoteID string `json:"quoteId"`
+	}
+
+	apiError struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+		Details struct {
+			Code string `json:"code"`
+		} `json:"details"`
+	}
+
 	cancelOrder struct {
 		Code    string `json:"cancellationCode"`
 		Details string `json:"details"`
@@ -0,0 +1,305 @@
+package orders
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/kpango/glg"
+)
+
+const (
+	// ItemAdded item only present on the update
+	ItemAdded ItemChangeType = "ADDED"
+	// ItemRemoved item only present on the current order
+	ItemRemoved ItemChangeType = "REMOVED"
+	// ItemChanged item present on both with different fields
+	ItemChanged ItemChangeType = "CHANGED"
+)
+
+type (
+	// ItemChangeType how an item differs on an update
+	ItemChangeType string
+
+	// OrderDiff differences between an order and a proposed update
+	OrderDiff struct {
+		Items   []ItemChange
+		Address *AddressChange
+		Amounts []AmountChange
+	}
+
+	// ItemChange item matched by external code, or name when it has none
+	//
+	// lines sharing a key are paired with an identical line first, then
+	// in order. Fields holds the json names of the changed fields of an
+	// ItemChanged
+	ItemChange struct {
+		Key    string
+		Type   ItemChangeType
+		Before *Item
+		After  *Item
+		Fields []string
+	}
+
+	// AddressChange delivery address before and after the update
+	AddressChange struct {
+		Before DeliveryAddress
+		After  DeliveryAddress
+	}
+
+	// AmountChange order amount before and after the update
+	AmountChange struct {
+		Field  string
+		Before string
+		After  string
+	}
+
+	// UpdateRequest is handed to the callback on UPDATE_REQUESTED
+	UpdateRequest struct {
+		OrderID   string
+		Reference string
+		Update    OrderUpdate
+		Diff      OrderDiff
+		Event     events.Event
+	}
+
+	// OrderUpdates keeps a snapshot of each order and follows
+	// its update requests through the polled events
+	//
+	// orders are identified by their id, the correlation id of the events,
+	// requests to the API are made with the reference of the snapshot
+	OrderUpdates struct {
+		service  Service
+		callback func(UpdateRequest)
+		mu       sync.Mutex
+		orders   map[string]OrderDetails
+		pending  map[string]UpdateRequest
+	}
+)
+
+// Empty reports if the update changes nothing on the order
+func (d OrderDiff) Empty() bool {
+	return len(d.Items) == 0 && d.Address == nil && len(d.Amounts) == 0
+}
+
+// DiffOrder compares the current order with a proposed update
+func DiffOrder(od OrderDetails, update OrderUpdate) (diff OrderDiff) {
+	if update.Items != nil {
+		diff.Items = diffItems(od.Items, update.Items)
+	}
+	if update.DeliveryAddress != nil && !reflect.DeepEqual(od.Deliveryaddress, *update.DeliveryAddress) {
+		diff.Address = &AddressChange{Before: od.Deliveryaddress, After: *update.DeliveryAddress}
+	}
+	amounts := []AmountChange{
+		{"subTotal", od.Subtotal, update.Subtotal},
+		{"deliveryFee", od.Deliveryfee, update.Deliveryfee},
+		{"totalPrice", od.Totalprice, update.Totalprice},
+	}
+	for _, amount := range amounts {
+		if amount.After != "" && !sameAmount(amount.Before, amount.After) {
+			diff.Amounts = append(diff.Amounts, amount)
+		}
+	}
+	return
+}
+
+func diffItems(before, after []Item) (changes []ItemChange) {
+	lines := make(map[string][]*Item)
+	for i := range before {
+		key := itemKey(before[i])
+		lines[key] = append(lines[key], &before[i])
+	}
+	matched := make(map[*Item]bool)
+	pairs := make([]*Item, len(after))
+	pair := func(identical bool) {
+		for i := range after {
+			if pairs[i] != nil {
+				continue
+			}
+			for _, previous := range lines[itemKey(after[i])] {
+				if !matched[previous] && (!identical || len(itemFields(*previous, after[i])) == 0) {
+					pairs[i], matched[previous] = previous, true
+					break
+				}
+			}
+		}
+	}
+	pair(true)
+	pair(false)
+	for i := range after {
+		item, previous, key := &after[i], pairs[i], itemKey(after[i])
+		if previous == nil {
+			changes = append(changes, ItemChange{Key: key, Type: ItemAdded, After: item})
+			continue
+		}
+		if fields := itemFields(*previous, *item); len(fields) > 0 {
+			changes = append(changes, ItemChange{Key: key, Type: ItemChanged, Before: previous, After: item, Fields: fields})
+		}
+	}
+	for i := range before {
+		if !matched[&before[i]] {
+			changes = append(changes, ItemChange{Key: itemKey(before[i]), Type: ItemRemoved, Before: &before[i]})
+		}
+	}
+	return
+}
+
+func itemKey(item Item) string {
+	if item.Externalcode != "" {
+		return item.Externalcode
+	}
+	return item.Name
+}
+
+func itemFields(before, after Item) (fields []string) {
+	values := []struct {
+		name          string
+		before, after string
+		amount        bool
+	}{
+		{"name", before.Name, after.Name, false},
+		{"quantity", before.Quantity, after.Quantity, true},
+		{"price", before.Price, after.Price, true},
+		{"totalPrice", before.Totalprice, after.Totalprice, true},
+		{"observations", before.Observations, after.Observations, false},
+	}
+	for _, v := range values {
+		if (v.amount && !sameAmount(v.before, v.after)) || (!v.amount && v.before != v.after) {
+			fields = append(fields, v.name)
+		}
+	}
+	if !reflect.DeepEqual(before.Subitems, after.Subitems) {
+		fields = append(fields, "subItems")
+	}
+	return
+}
+
+// sameAmount compares amounts by value, so "10" equals "10.00"
+func sameAmount(a, b string) bool {
+	x, errA := ParseAmount(a)
+	y, errB := ParseAmount(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return roundCents(x) == roundCents(y)
+}
+
+// NewOrderUpdates returns an update tracker, callback is called
+// with the diff of each UPDATE_REQUESTED event
+func NewOrderUpdates(service Service, callback func(UpdateRequest)) *OrderUpdates {
+	return &OrderUpdates{
+		service:  service,
+		callback: callback,
+		orders:   make(map[string]OrderDetails),
+		pending:  make(map[string]UpdateRequest),
+	}
+}
+
+// Track keeps the order snapshot used as the base of the diffs
+func (u *OrderUpdates) Track(od OrderDetails) {
+	u.mu.Lock()
+	u.orders[od.ID] = od
+	u.mu.Unlock()
+}
+
+// Order returns the order snapshot by the order id
+func (u *OrderUpdates) Order(orderID string) (od OrderDetails, ok bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	od, ok = u.orders[orderID]
+	return
+}
+
+// Pending returns the update request of the order id waiting for an answer
+func (u *OrderUpdates) Pending(orderID string) (ur UpdateRequest, ok bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	ur, ok = u.pending[orderID]
+	return
+}
+
+// Accept accepts the pending update of the order id, the
+// snapshot is refreshed when the UPDATED event arrives
+func (u *OrderUpdates) Accept(orderID string) error {
+	return u.answer(orderID, true)
+}
+
+// Deny denies the pending update of the order id
+func (u *OrderUpdates) Deny(orderID string) error {
+	return u.answer(orderID, false)
+}
+
+func (u *OrderUpdates) answer(orderID string, accepted bool) (err error) {
+	if err = u.service.UpdateRequestStatus(u.reference(orderID), accepted); err != nil {
+		glg.Error("[SDK] Orders OrderUpdates UpdateRequestStatus: ", err.Error())
+		return
+	}
+	u.mu.Lock()
+	delete(u.pending, orderID)
+	u.mu.Unlock()
+	return
+}
+
+// reference of a tracked order, orders not tracked only have the
+// id of their events, which is used instead
+func (u *OrderUpdates) reference(orderID string) string {
+	if od, ok := u.Order(orderID); ok && (od.Reference != "") {
+		return od.Reference
+	}
+	return orderID
+}
+
+// HandleEvent fetches and diffs the update on UPDATE_REQUESTED,
+// drops it on UPDATE_DENIED and refreshes the snapshot on UPDATED
+func (u *OrderUpdates) HandleEvent(event events.Event) (err error) {
+	orderID := event.CorrelationID
+	switch event.Name() {
+	case "UPDATE_REQUESTED":
+		return u.requested(event)
+	case "UPDATE_DENIED":
+		u.mu.Lock()
+		delete(u.pending, orderID)
+		u.mu.Unlock()
+	case "UPDATED":
+		u.mu.Lock()
+		delete(u.pending, orderID)
+		u.mu.Unlock()
+		od, err := u.service.GetDetails(u.reference(orderID))
+		if err != nil {
+			glg.Error("[SDK] Orders OrderUpdates GetDetails: ", err.Error())
+			return err
+		}
+		u.Track(od)
+	case "CANCELLED", "CONCLUDED":
+		u.mu.Lock()
+		delete(u.orders, orderID)
+		delete(u.pending, orderID)
+		u.mu.Unlock()
+	}
+	return
+}
+
+func (u *OrderUpdates) requested(event events.Event) (err error) {
+	orderID := event.CorrelationID
+	od, ok := u.Order(orderID)
+	if !ok {
+		if od, err = u.service.GetDetails(orderID); err != nil {
+			glg.Error("[SDK] Orders OrderUpdates GetDetails: ", err.Error())
+			return
+		}
+		u.Track(od)
+	}
+	update, err := u.service.GetUpdateRequest(u.reference(orderID))
+	if err != nil {
+		glg.Error("[SDK] Orders OrderUpdates GetUpdateRequest: ", err.Error())
+		return
+	}
+	ur := UpdateRequest{OrderID: orderID, Reference: od.Reference, Update: update, Diff: DiffOrder(od, update), Event: event}
+	u.mu.Lock()
+	u.pending[orderID] = ur
+	u.mu.Unlock()
+	if u.callback != nil {
+		u.callback(ur)
+	}
+	return
+}
@@ -0,0 +1,241 @@
+package orders
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+)
+
+const updateRequest = `{
+	"id": "update_id",
+	"reason": "ITEM_CHANGE",
+	"items": [
+		{"name": "Pizza", "externalCode": "P1", "quantity": "2", "price": "30", "totalPrice": "60"},
+		{"name": "Suco", "externalCode": "S1", "quantity": "1", "price": "8", "totalPrice": "8"}
+	],
+	"subTotal": "68.00",
+	"totalPrice": "73"
+}`
+
+// updateBase reference differs from its id, events carry the id
+var updateBase = OrderDetails{
+	ID:        "order_id",
+	Reference: "7788",
+	Items: []Item{
+		{Name: "Pizza", Externalcode: "P1", Quantity: "1", Price: "30", Totalprice: "30"},
+		{Name: "Refrigerante", Quantity: "1", Price: "5", Totalprice: "5"},
+	},
+	Deliveryaddress: DeliveryAddress{Streetname: "Rua A", Streetnumber: "10"},
+	Subtotal:        "35",
+	Deliveryfee:     "5",
+	Totalprice:      "40",
+}
+
+func TestGetUpdateRequest_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v2.0/orders/reference_id/update-request", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			assert.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, updateRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	ou, err := ordersService.GetUpdateRequest("reference_id")
+	assert.Nil(t, err)
+	assert.Equal(t, "update_id", ou.ID)
+	assert.Equal(t, 2, len(ou.Items))
+	assert.Nil(t, ou.DeliveryAddress)
+}
+
+func TestGetUpdateRequest_NoReferenceID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "ts.URL")
+	ordersService := New(adapter, &am)
+	_, err := ordersService.GetUpdateRequest("")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
+
+func TestUpdateRequestStatus(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Method, http.MethodPost)
+			paths = append(paths, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	assert.Nil(t, ordersService.UpdateRequestStatus("reference_id", true))
+	assert.Nil(t, ordersService.UpdateRequestStatus("reference_id", false))
+	assert.Equal(t, []string{
+		"/v2.0/orders/reference_id/update-request/accept",
+		"/v2.0/orders/reference_id/update-request/deny",
+	}, paths)
+}
+
+func TestUpdateRequestStatus_StatusBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":{"code":"UpdateRequestExpired"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.UpdateRequestStatus("reference_id", true)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "UpdateRequestExpired")
+}
+
+func TestDiffOrder(t *testing.T) {
+	address := DeliveryAddress{Streetname: "Rua B", Streetnumber: "20"}
+	diff := DiffOrder(updateBase, OrderUpdate{
+		Items: []Item{
+			{Name: "Pizza", Externalcode: "P1", Quantity: "2", Price: "30.00", Totalprice: "60"},
+			{Name: "Suco", Externalcode: "S1", Quantity: "1", Price: "8", Totalprice: "8"},
+		},
+		DeliveryAddress: &address,
+		Subtotal:        "68",
+		Deliveryfee:     "5.00",
+	})
+	assert.False(t, diff.Empty())
+	assert.Equal(t, 3, len(diff.Items))
+	assert.Equal(t, ItemChanged, diff.Items[0].Type)
+	assert.Equal(t, []string{"quantity", "totalPrice"}, diff.Items[0].Fields)
+	assert.Equal(t, ItemAdded, diff.Items[1].Type)
+	assert.Equal(t, "S1", diff.Items[1].Key)
+	assert.Equal(t, ItemRemoved, diff.Items[2].Type)
+	assert.Equal(t, "Refrigerante", diff.Items[2].Before.Name)
+	assert.Equal(t, "Rua B", diff.Address.After.Streetname)
+	assert.Equal(t, []AmountChange{{"subTotal", "35", "68"}}, diff.Amounts)
+
+	assert.True(t, DiffOrder(updateBase, OrderUpdate{Totalprice: "40.00"}).Empty())
+}
+
+func TestDiffOrder_DuplicateLines(t *testing.T) {
+	plain := Item{Name: "Pizza", Quantity: "1", Price: "30", Totalprice: "30"}
+	onion := Item{Name: "Pizza", Quantity: "1", Price: "30", Totalprice: "30", Observations: "sem cebola"}
+	order := OrderDetails{Items: []Item{plain, onion}}
+
+	// removing one of two lines of the same product
+	diff := DiffOrder(order, OrderUpdate{Items: []Item{onion}})
+	assert.Equal(t, 1, len(diff.Items))
+	assert.Equal(t, ItemRemoved, diff.Items[0].Type)
+	assert.Equal(t, "", diff.Items[0].Before.Observations)
+
+	// a third line of the product is added, the others are unchanged
+	diff = DiffOrder(order, OrderUpdate{Items: []Item{plain, onion, plain}})
+	assert.Equal(t, 1, len(diff.Items))
+	assert.Equal(t, ItemAdded, diff.Items[0].Type)
+	assert.Equal(t, "Pizza", diff.Items[0].Key)
+
+	// lines without an identical match are paired in order
+	diff = DiffOrder(order, OrderUpdate{Items: []Item{onion, {Name: "Pizza", Quantity: "2", Price: "30", Totalprice: "60"}}})
+	assert.Equal(t, 1, len(diff.Items))
+	assert.Equal(t, ItemChanged, diff.Items[0].Type)
+	assert.Equal(t, []string{"quantity", "totalPrice"}, diff.Items[0].Fields)
+}
+
+func TestOrderUpdates_Events(t *testing.T) {
+	var answers []string
+	details := 0
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2.0/orders/7788/update-request":
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, updateRequest)
+			case "/v3.0/orders/7788":
+				details++
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, `{"id":"order_id","reference":"7788","subTotal":"68","totalPrice":"73"}`)
+			default:
+				answers = append(answers, r.URL.Path)
+				w.WriteHeader(http.StatusAccepted)
+			}
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	var requests []UpdateRequest
+	updates := NewOrderUpdates(New(adapter, &am), func(ur UpdateRequest) { requests = append(requests, ur) })
+	updates.Track(updateBase)
+	_, ok := updates.Order("7788")
+	assert.False(t, ok)
+	_, ok = updates.Order("order_id")
+	assert.True(t, ok)
+
+	assert.Nil(t, updates.HandleEvent(events.Event{Code: "UPR", CorrelationID: "order_id"}))
+	assert.Equal(t, 1, len(requests))
+	// the snapshot tracked by id was found, no details were fetched
+	assert.Equal(t, 0, details)
+	assert.Equal(t, "order_id", requests[0].OrderID)
+	assert.Equal(t, "7788", requests[0].Reference)
+	assert.Equal(t, "update_id", requests[0].Update.ID)
+	assert.Equal(t, 3, len(requests[0].Diff.Items))
+	_, ok = updates.Pending("order_id")
+	assert.True(t, ok)
+
+	assert.Nil(t, updates.HandleEvent(events.Event{Code: "UPD", CorrelationID: "order_id"}))
+	_, ok = updates.Pending("order_id")
+	assert.False(t, ok)
+
+	assert.Nil(t, updates.HandleEvent(events.Event{Code: "UPR", CorrelationID: "order_id"}))
+	assert.Nil(t, updates.Accept("order_id"))
+	assert.Equal(t, []string{"/v2.0/orders/7788/update-request/accept"}, answers)
+	_, ok = updates.Pending("order_id")
+	assert.False(t, ok)
+
+	assert.Nil(t, updates.HandleEvent(events.Event{Code: "UPT", CorrelationID: "order_id"}))
+	assert.Equal(t, 1, details)
+	od, ok := updates.Order("order_id")
+	assert.True(t, ok)
+	assert.Equal(t, "73", od.Totalprice)
+
+	assert.Nil(t, updates.HandleEvent(events.Event{Code: "CAN", CorrelationID: "order_id"}))
+	_, ok = updates.Order("order_id")
+	assert.False(t, ok)
+}
+
+func TestOrderUpdates_FetchesUnknownOrder(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	called := false
+	updates := NewOrderUpdates(New(adapter, &am), func(UpdateRequest) { called = true })
+	err := updates.HandleEvent(events.Event{Code: "UPR", CorrelationID: "reference_id"})
+	assert.NotNil(t, err)
+	assert.False(t, called)
+}